@@ -70,6 +70,24 @@ type (
 	}
 )
 
+// ForInstrumentType returns the leverage of the instrument type, it returns false for an unknown type.
+func (l Leverages) ForInstrumentType(instrumentType string) (Leverage, bool) {
+	switch instrumentType {
+	case InstrumentTypeShares:
+		return l.Shares, true
+	case InstrumentTypeCurrencies:
+		return l.Currencies, true
+	case InstrumentTypeIndices:
+		return l.Indices, true
+	case InstrumentTypeCryptocurrencies:
+		return l.Cryptocurrencies, true
+	case InstrumentTypeCommodities:
+		return l.Commodities, true
+	default:
+		return Leverage{}, false
+	}
+}
+
 // Preferences retrieves the preferences of the authenticated user.
 func (a *account) Preferences(ctx context.Context) (*Preferences, error) {
 	headers := a.tokens.headers()
@@ -0,0 +1,368 @@
+package capitalcom
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// MarketOrderBuilder builds an OpenPositionRequest. Stop and take profit setters replace previously set ones,
+// so mutually exclusive fields can't be combined, e.g. a trailing stop always has a distance
+// and is never guaranteed.
+type MarketOrderBuilder struct {
+	req        OpenPositionRequest
+	stops      stopsBuilder
+	violations []Violation
+}
+
+// NewMarketOrder starts building a request to open a position at the market price.
+func NewMarketOrder(epic string, direction PositionDirection, size float64) *MarketOrderBuilder {
+	b := &MarketOrderBuilder{
+		req: OpenPositionRequest{
+			Direction: direction,
+			Epic:      epic,
+			Size:      size,
+		},
+	}
+
+	b.violations = validateDeal(epic, direction, size)
+
+	return b
+}
+
+// WithStopLevel sets a stop loss at the price level.
+func (b *MarketOrderBuilder) WithStopLevel(level float64) *MarketOrderBuilder {
+	b.stops.setStop("stopLevel", level, stopsAndProfits{stopLevel: level})
+
+	return b
+}
+
+// WithStopDistance sets a stop loss at the distance from the current price.
+func (b *MarketOrderBuilder) WithStopDistance(distance float64) *MarketOrderBuilder {
+	b.stops.setStop("stopDistance", distance, stopsAndProfits{stopDistance: distance})
+
+	return b
+}
+
+// WithStopAmount sets a stop loss triggered when the loss reaches the amount.
+func (b *MarketOrderBuilder) WithStopAmount(amount float64) *MarketOrderBuilder {
+	b.stops.setStop("stopAmount", amount, stopsAndProfits{stopAmount: amount})
+
+	return b
+}
+
+// WithTrailingStop sets a trailing stop loss at the distance from the current price.
+func (b *MarketOrderBuilder) WithTrailingStop(distance float64) *MarketOrderBuilder {
+	b.stops.setStop("stopDistance", distance, stopsAndProfits{trailingStop: true, stopDistance: distance})
+
+	return b
+}
+
+// WithGuaranteedStopLevel sets a guaranteed stop loss at the price level.
+// Guaranteed stops are not allowed in the hedging mode.
+func (b *MarketOrderBuilder) WithGuaranteedStopLevel(level float64) *MarketOrderBuilder {
+	b.stops.setStop("stopLevel", level, stopsAndProfits{guaranteedStop: true, stopLevel: level})
+
+	return b
+}
+
+// WithGuaranteedStopDistance sets a guaranteed stop loss at the distance from the current price.
+// Guaranteed stops are not allowed in the hedging mode.
+func (b *MarketOrderBuilder) WithGuaranteedStopDistance(distance float64) *MarketOrderBuilder {
+	b.stops.setStop("stopDistance", distance, stopsAndProfits{guaranteedStop: true, stopDistance: distance})
+
+	return b
+}
+
+// WithGuaranteedStopAmount sets a guaranteed stop loss triggered when the loss reaches the amount.
+// Guaranteed stops are not allowed in the hedging mode.
+func (b *MarketOrderBuilder) WithGuaranteedStopAmount(amount float64) *MarketOrderBuilder {
+	b.stops.setStop("stopAmount", amount, stopsAndProfits{guaranteedStop: true, stopAmount: amount})
+
+	return b
+}
+
+// WithTakeProfitLevel sets a take profit at the price level.
+func (b *MarketOrderBuilder) WithTakeProfitLevel(level float64) *MarketOrderBuilder {
+	b.stops.setProfit("profitLevel", level, stopsAndProfits{profitLevel: level})
+
+	return b
+}
+
+// WithTakeProfitDistance sets a take profit at the distance from the current price.
+func (b *MarketOrderBuilder) WithTakeProfitDistance(distance float64) *MarketOrderBuilder {
+	b.stops.setProfit("profitDistance", distance, stopsAndProfits{profitDistance: distance})
+
+	return b
+}
+
+// WithTakeProfitAmount sets a take profit triggered when the profit reaches the amount.
+func (b *MarketOrderBuilder) WithTakeProfitAmount(amount float64) *MarketOrderBuilder {
+	b.stops.setProfit("profitAmount", amount, stopsAndProfits{profitAmount: amount})
+
+	return b
+}
+
+// Build returns the request or a ValidationError if any of the provided values is invalid.
+// Use Validator to check the request against the dealing rules of the market.
+func (b *MarketOrderBuilder) Build() (OpenPositionRequest, error) {
+	if err := newValidationError(slices.Concat(b.violations, b.stops.violations())); err != nil {
+		return OpenPositionRequest{}, err
+	}
+
+	req := b.req
+	req.UpdatePositionRequest = b.stops.positionRequest()
+
+	return req, nil
+}
+
+// WorkingOrderBuilder builds a CreateOrderRequest. Stop and take profit setters replace previously set ones,
+// so mutually exclusive fields can't be combined, e.g. a trailing stop always has a distance
+// and is never guaranteed.
+type WorkingOrderBuilder struct {
+	req        CreateOrderRequest
+	stops      stopsBuilder
+	violations []Violation
+}
+
+// NewLimitOrder starts building a request to create a limit working order at the level.
+func NewLimitOrder(epic string, direction PositionDirection, size, level float64) *WorkingOrderBuilder {
+	return newWorkingOrderBuilder(LimitOrder, epic, direction, size, level)
+}
+
+// NewStopOrder starts building a request to create a stop working order at the level.
+func NewStopOrder(epic string, direction PositionDirection, size, level float64) *WorkingOrderBuilder {
+	return newWorkingOrderBuilder(StopOrder, epic, direction, size, level)
+}
+
+func newWorkingOrderBuilder(
+	orderType OrderType,
+	epic string,
+	direction PositionDirection,
+	size float64,
+	level float64,
+) *WorkingOrderBuilder {
+	b := &WorkingOrderBuilder{
+		req: CreateOrderRequest{
+			Direction: direction,
+			Epic:      epic,
+			Size:      size,
+			Type:      orderType,
+			UpdateOrderRequest: UpdateOrderRequest{
+				Level: level,
+			},
+		},
+	}
+
+	b.violations = validateDeal(epic, direction, size)
+
+	if level <= 0 {
+		b.violations = append(b.violations, Violation{Field: "level", Message: "must be positive"})
+	}
+
+	return b
+}
+
+// WithGoodTillDate sets the date when the order is cancelled and the time in force to GOOD_TILL_DATE,
+// by default the time in force isn't sent and the order is good till cancelled.
+func (b *WorkingOrderBuilder) WithGoodTillDate(date time.Time) *WorkingOrderBuilder {
+	b.req.GoodTillDate = date
+	b.req.TimeInForce = TimeInForceGoodTillDate
+
+	if date.IsZero() {
+		b.violations = append(b.violations, Violation{Field: "goodTillDate", Message: "must be set"})
+	}
+
+	return b
+}
+
+// WithGoodTillCancelled sets the time in force to GOOD_TILL_CANCELLED explicitly, discarding the date
+// set by WithGoodTillDate.
+func (b *WorkingOrderBuilder) WithGoodTillCancelled() *WorkingOrderBuilder {
+	b.req.GoodTillDate = time.Time{}
+	b.req.TimeInForce = TimeInForceGoodTillCancelled
+	b.violations = slices.DeleteFunc(b.violations, func(v Violation) bool {
+		return v.Field == "goodTillDate"
+	})
+
+	return b
+}
+
+// WithStopLevel sets a stop loss at the price level.
+func (b *WorkingOrderBuilder) WithStopLevel(level float64) *WorkingOrderBuilder {
+	b.stops.setStop("stopLevel", level, stopsAndProfits{stopLevel: level})
+
+	return b
+}
+
+// WithStopDistance sets a stop loss at the distance from the order level.
+func (b *WorkingOrderBuilder) WithStopDistance(distance float64) *WorkingOrderBuilder {
+	b.stops.setStop("stopDistance", distance, stopsAndProfits{stopDistance: distance})
+
+	return b
+}
+
+// WithStopAmount sets a stop loss triggered when the loss reaches the amount.
+func (b *WorkingOrderBuilder) WithStopAmount(amount float64) *WorkingOrderBuilder {
+	b.stops.setStop("stopAmount", amount, stopsAndProfits{stopAmount: amount})
+
+	return b
+}
+
+// WithTrailingStop sets a trailing stop loss at the distance.
+func (b *WorkingOrderBuilder) WithTrailingStop(distance float64) *WorkingOrderBuilder {
+	b.stops.setStop("stopDistance", distance, stopsAndProfits{trailingStop: true, stopDistance: distance})
+
+	return b
+}
+
+// WithGuaranteedStopLevel sets a guaranteed stop loss at the price level.
+// Guaranteed stops are not allowed in the hedging mode.
+func (b *WorkingOrderBuilder) WithGuaranteedStopLevel(level float64) *WorkingOrderBuilder {
+	b.stops.setStop("stopLevel", level, stopsAndProfits{guaranteedStop: true, stopLevel: level})
+
+	return b
+}
+
+// WithGuaranteedStopDistance sets a guaranteed stop loss at the distance from the order level.
+// Guaranteed stops are not allowed in the hedging mode.
+func (b *WorkingOrderBuilder) WithGuaranteedStopDistance(distance float64) *WorkingOrderBuilder {
+	b.stops.setStop("stopDistance", distance, stopsAndProfits{guaranteedStop: true, stopDistance: distance})
+
+	return b
+}
+
+// WithGuaranteedStopAmount sets a guaranteed stop loss triggered when the loss reaches the amount.
+// Guaranteed stops are not allowed in the hedging mode.
+func (b *WorkingOrderBuilder) WithGuaranteedStopAmount(amount float64) *WorkingOrderBuilder {
+	b.stops.setStop("stopAmount", amount, stopsAndProfits{guaranteedStop: true, stopAmount: amount})
+
+	return b
+}
+
+// WithTakeProfitLevel sets a take profit at the price level.
+func (b *WorkingOrderBuilder) WithTakeProfitLevel(level float64) *WorkingOrderBuilder {
+	b.stops.setProfit("profitLevel", level, stopsAndProfits{profitLevel: level})
+
+	return b
+}
+
+// WithTakeProfitDistance sets a take profit at the distance from the order level.
+func (b *WorkingOrderBuilder) WithTakeProfitDistance(distance float64) *WorkingOrderBuilder {
+	b.stops.setProfit("profitDistance", distance, stopsAndProfits{profitDistance: distance})
+
+	return b
+}
+
+// WithTakeProfitAmount sets a take profit triggered when the profit reaches the amount.
+func (b *WorkingOrderBuilder) WithTakeProfitAmount(amount float64) *WorkingOrderBuilder {
+	b.stops.setProfit("profitAmount", amount, stopsAndProfits{profitAmount: amount})
+
+	return b
+}
+
+// Build returns the request or a ValidationError if any of the provided values is invalid.
+// Use Validator to check the request against the dealing rules of the market.
+func (b *WorkingOrderBuilder) Build() (CreateOrderRequest, error) {
+	if err := newValidationError(slices.Concat(b.violations, b.stops.violations())); err != nil {
+		return CreateOrderRequest{}, err
+	}
+
+	req := b.req
+	req.UpdateOrderRequest = b.stops.orderRequest(req.Level, req.GoodTillDate)
+	req.TimeInForce = b.req.TimeInForce
+
+	return req, nil
+}
+
+func validateDeal(epic string, direction PositionDirection, size float64) []Violation {
+	violations := validateDirection(direction)
+
+	if epic == "" {
+		violations = append(violations, Violation{Field: "epic", Message: "must not be empty"})
+	}
+
+	if size <= 0 {
+		violations = append(violations, Violation{Field: "size", Message: "must be positive"})
+	}
+
+	return violations
+}
+
+// stopsBuilder keeps the stop loss and take profit fields along with the last set values to validate them.
+type stopsBuilder struct {
+	stopsAndProfits
+
+	stopField   string
+	stopValue   float64
+	profitField string
+	profitValue float64
+}
+
+// setStop replaces the stop loss fields with the stop ones, the take profit fields are kept.
+func (s *stopsBuilder) setStop(field string, value float64, stop stopsAndProfits) {
+	s.guaranteedStop = stop.guaranteedStop
+	s.trailingStop = stop.trailingStop
+	s.stopLevel = stop.stopLevel
+	s.stopDistance = stop.stopDistance
+	s.stopAmount = stop.stopAmount
+
+	s.stopField = field
+	s.stopValue = value
+}
+
+// setProfit replaces the take profit fields with the profit ones, the stop loss fields are kept.
+func (s *stopsBuilder) setProfit(field string, value float64, profit stopsAndProfits) {
+	s.profitLevel = profit.profitLevel
+	s.profitDistance = profit.profitDistance
+	s.profitAmount = profit.profitAmount
+
+	s.profitField = field
+	s.profitValue = value
+}
+
+func (s *stopsBuilder) violations() []Violation {
+	var violations []Violation
+
+	if s.stopField != "" && s.stopValue <= 0 {
+		violations = append(violations, nonPositiveViolation(s.stopField, s.stopValue))
+	}
+
+	if s.profitField != "" && s.profitValue <= 0 {
+		violations = append(violations, nonPositiveViolation(s.profitField, s.profitValue))
+	}
+
+	return violations
+}
+
+func (s *stopsAndProfits) positionRequest() UpdatePositionRequest {
+	return UpdatePositionRequest{
+		GuaranteedStop: s.guaranteedStop,
+		TrailingStop:   s.trailingStop,
+		StopLevel:      s.stopLevel,
+		StopDistance:   s.stopDistance,
+		StopAmount:     s.stopAmount,
+		ProfitLevel:    s.profitLevel,
+		ProfitDistance: s.profitDistance,
+		ProfitAmount:   s.profitAmount,
+	}
+}
+
+func (s *stopsAndProfits) orderRequest(level float64, goodTillDate time.Time) UpdateOrderRequest {
+	return UpdateOrderRequest{
+		Level:          level,
+		GoodTillDate:   goodTillDate,
+		GuaranteedStop: s.guaranteedStop,
+		TrailingStop:   s.trailingStop,
+		StopLevel:      s.stopLevel,
+		StopDistance:   s.stopDistance,
+		StopAmount:     s.stopAmount,
+		ProfitLevel:    s.profitLevel,
+		ProfitDistance: s.profitDistance,
+		ProfitAmount:   s.profitAmount,
+	}
+}
+
+func nonPositiveViolation(field string, value float64) Violation {
+	return Violation{Field: field, Message: fmt.Sprintf("must be positive, got %g", value)}
+}
@@ -0,0 +1,196 @@
+package capitalcom_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketOrderBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		builder  *capitalcom.MarketOrderBuilder
+		expected capitalcom.UpdatePositionRequest
+	}{
+		{
+			name: "trailing stop with take profit",
+			builder: capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionBuy, 1).
+				WithTrailingStop(0.5).
+				WithTakeProfitLevel(30),
+			expected: capitalcom.UpdatePositionRequest{
+				TrailingStop: true,
+				StopDistance: 0.5,
+				ProfitLevel:  30,
+			},
+		},
+		{
+			name: "trailing stop replaces guaranteed stop",
+			builder: capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionBuy, 1).
+				WithGuaranteedStopLevel(20).
+				WithTrailingStop(0.5),
+			expected: capitalcom.UpdatePositionRequest{
+				TrailingStop: true,
+				StopDistance: 0.5,
+			},
+		},
+		{
+			name: "guaranteed stop replaces trailing stop",
+			builder: capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionBuy, 1).
+				WithTrailingStop(0.5).
+				WithGuaranteedStopAmount(10),
+			expected: capitalcom.UpdatePositionRequest{
+				GuaranteedStop: true,
+				StopAmount:     10,
+			},
+		},
+		{
+			name: "only the last stop is kept",
+			builder: capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionSell, 1).
+				WithStopLevel(30).
+				WithStopAmount(10).
+				WithStopDistance(2),
+			expected: capitalcom.UpdatePositionRequest{
+				StopDistance: 2,
+			},
+		},
+		{
+			name: "only the last take profit is kept",
+			builder: capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionSell, 1).
+				WithTakeProfitDistance(2).
+				WithTakeProfitLevel(20).
+				WithTakeProfitAmount(15),
+			expected: capitalcom.UpdatePositionRequest{
+				ProfitAmount: 15,
+			},
+		},
+		{
+			name: "replacing an invalid stop drops its violation",
+			builder: capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionSell, 1).
+				WithGuaranteedStopDistance(-1).
+				WithGuaranteedStopDistance(1),
+			expected: capitalcom.UpdatePositionRequest{
+				GuaranteedStop: true,
+				StopDistance:   1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := tt.builder.Build()
+
+			require.NoError(t, err)
+			require.Equal(t, "SILVER", req.Epic)
+			require.InDelta(t, 1, req.Size, 0)
+			require.Equal(t, tt.expected, req.UpdatePositionRequest)
+		})
+	}
+}
+
+func TestMarketOrderBuilder_BuildReturnsViolations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		builder    *capitalcom.MarketOrderBuilder
+		violations []string
+	}{
+		{
+			name:       "invalid deal",
+			builder:    capitalcom.NewMarketOrder("", "HOLD", 0),
+			violations: []string{"direction", "epic", "size"},
+		},
+		{
+			name:       "trailing stop without distance",
+			builder:    capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionBuy, 1).WithTrailingStop(0),
+			violations: []string{"stopDistance"},
+		},
+		{
+			name:       "guaranteed stop without level",
+			builder:    capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionBuy, 1).WithGuaranteedStopLevel(0),
+			violations: []string{"stopLevel"},
+		},
+		{
+			name: "negative stop and profit",
+			builder: capitalcom.NewMarketOrder("SILVER", capitalcom.PositionDirectionBuy, 1).
+				WithStopAmount(-5).
+				WithTakeProfitDistance(-1),
+			violations: []string{"stopAmount", "profitDistance"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.builder.Build()
+
+			requireViolations(t, err, tt.violations...)
+		})
+	}
+}
+
+func TestWorkingOrderBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	goodTillDate := time.Date(2025, time.January, 2, 15, 0, 0, 0, time.UTC)
+
+	// Act
+	req, err := capitalcom.NewLimitOrder("SILVER", capitalcom.PositionDirectionBuy, 2, 24.5).
+		WithGoodTillDate(goodTillDate).
+		WithGuaranteedStopDistance(1).
+		WithTakeProfitLevel(27).
+		Build()
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, capitalcom.CreateOrderRequest{
+		Direction: capitalcom.PositionDirectionBuy,
+		Epic:      "SILVER",
+		Size:      2,
+		Type:      capitalcom.LimitOrder,
+		UpdateOrderRequest: capitalcom.UpdateOrderRequest{
+			Level:          24.5,
+			GoodTillDate:   goodTillDate,
+			TimeInForce:    capitalcom.TimeInForceGoodTillDate,
+			GuaranteedStop: true,
+			StopDistance:   1,
+			ProfitLevel:    27,
+		},
+	}, req)
+}
+
+func TestWorkingOrderBuilder_BuildGoodTillCancelled(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	req, err := capitalcom.NewLimitOrder("SILVER", capitalcom.PositionDirectionBuy, 2, 24.5).
+		WithGoodTillDate(time.Time{}).
+		WithGoodTillCancelled().
+		Build()
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, req.GoodTillDate.IsZero())
+	require.Equal(t, capitalcom.TimeInForceGoodTillCancelled, req.TimeInForce)
+}
+
+func TestWorkingOrderBuilder_BuildReturnsViolations(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	_, err := capitalcom.NewStopOrder("SILVER", capitalcom.PositionDirectionSell, 2, 0).
+		WithGoodTillDate(time.Time{}).
+		WithTrailingStop(-1).
+		Build()
+
+	// Assert
+	requireViolations(t, err, "level", "goodTillDate", "stopDistance")
+}
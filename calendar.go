@@ -0,0 +1,230 @@
+package capitalcom
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+// Session is a continuous period the market is open, the Close is exclusive.
+type Session struct {
+	Open  time.Time
+	Close time.Time
+}
+
+// TradingCalendar is a weekly schedule of a market built from its opening hours, see OpeningHours.Calendar.
+// Ranges of the adjacent days, e.g. "22:05 - 00:00" on Sunday and "00:00 - 21:00" on Monday, are joined
+// into a single session. It is safe for concurrent use.
+type TradingCalendar struct {
+	location *time.Location
+	// ranges are minutes since Monday 00:00 in the location, a range may end in the next week
+	ranges     []weeklyRange
+	alwaysOpen bool
+}
+
+type weeklyRange struct {
+	start int
+	end   int
+}
+
+// Calendar parses the opening hours into a trading calendar. The ranges are in the "HH:MM - HH:MM" format
+// in the time zone of the opening hours, UTC if the zone is empty. A range ending at or before its start,
+// e.g. "22:00 - 06:00" or "00:00 - 00:00", ends on the next day.
+func (o OpeningHours) Calendar() (*TradingCalendar, error) {
+	location := time.UTC
+
+	if o.Zone != "" {
+		var err error
+
+		location, err = time.LoadLocation(o.Zone)
+		if err != nil {
+			return nil, NewOpeningHoursParsingError(o.Zone, err)
+		}
+	}
+
+	var ranges []weeklyRange
+
+	for day, dayRanges := range [][]string{o.Mon, o.Tue, o.Wed, o.Thu, o.Fri, o.Sat, o.Sun} {
+		for _, value := range dayRanges {
+			r, err := parseDayRange(value)
+			if err != nil {
+				return nil, err
+			}
+
+			r.start += day * minutesPerDay
+			r.end += day * minutesPerDay
+
+			ranges = append(ranges, r)
+		}
+	}
+
+	calendar := &TradingCalendar{location: location}
+	calendar.ranges, calendar.alwaysOpen = mergeWeeklyRanges(ranges)
+
+	return calendar, nil
+}
+
+// IsOpen reports whether the market is open at the time.
+func (c *TradingCalendar) IsOpen(t time.Time) bool {
+	if c.alwaysOpen {
+		return true
+	}
+
+	session, ok := c.firstSession(t)
+
+	return ok && !session.Open.After(t)
+}
+
+// NextOpen returns the time the market opens after the time, it returns false if the market
+// never opens or never closes.
+func (c *TradingCalendar) NextOpen(t time.Time) (time.Time, bool) {
+	for session := range c.sessionsFrom(t) {
+		if session.Open.After(t) {
+			return session.Open, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// NextClose returns the time the market closes after the time, which is the end of the current session
+// if the market is open. It returns false if the market never opens or never closes.
+func (c *TradingCalendar) NextClose(t time.Time) (time.Time, bool) {
+	session, ok := c.firstSession(t)
+
+	return session.Close, ok
+}
+
+// Sessions returns the sessions overlapping the time range in order, the first and the last sessions
+// may start before the range and end after it. For a market open around the clock a single session
+// equal to the range is returned.
+func (c *TradingCalendar) Sessions(from, to time.Time) iter.Seq[Session] {
+	return func(yield func(Session) bool) {
+		if !from.Before(to) {
+			return
+		}
+
+		if c.alwaysOpen {
+			yield(Session{Open: from, Close: to})
+
+			return
+		}
+
+		for session := range c.sessionsFrom(from) {
+			if !session.Open.Before(to) || !yield(session) {
+				return
+			}
+		}
+	}
+}
+
+// firstSession returns the current session or the next one if the market is closed.
+func (c *TradingCalendar) firstSession(t time.Time) (Session, bool) {
+	next, stop := iter.Pull(c.sessionsFrom(t))
+	defer stop()
+
+	return next()
+}
+
+// sessionsFrom returns the endless sequence of the sessions closing after the time.
+func (c *TradingCalendar) sessionsFrom(t time.Time) iter.Seq[Session] {
+	return func(yield func(Session) bool) {
+		if c.alwaysOpen || len(c.ranges) == 0 {
+			return
+		}
+
+		local := t.In(c.location)
+		// a session of the previous week may still be open
+		weekStart := local.AddDate(0, 0, -(int(local.Weekday())+6)%7-7) //nolint:mnd
+
+		for {
+			year, month, day := weekStart.Date()
+
+			for _, r := range c.ranges {
+				session := Session{
+					Open:  time.Date(year, month, day, 0, r.start, 0, 0, c.location),
+					Close: time.Date(year, month, day, 0, r.end, 0, 0, c.location),
+				}
+
+				if session.Close.After(t) && !yield(session) {
+					return
+				}
+			}
+
+			weekStart = time.Date(year, month, day+7, 0, 0, 0, 0, c.location) //nolint:mnd
+		}
+	}
+}
+
+// parseDayRange parses the "HH:MM - HH:MM" range into minutes since midnight.
+func parseDayRange(value string) (weeklyRange, error) {
+	startValue, endValue, ok := strings.Cut(value, "-")
+	if !ok {
+		return weeklyRange{}, NewOpeningHoursParsingError(value, ErrInvalidOpeningHoursRange)
+	}
+
+	start, err := time.Parse("15:04", strings.TrimSpace(startValue))
+	if err != nil {
+		return weeklyRange{}, NewOpeningHoursParsingError(value, err)
+	}
+
+	end, err := time.Parse("15:04", strings.TrimSpace(endValue))
+	if err != nil {
+		return weeklyRange{}, NewOpeningHoursParsingError(value, err)
+	}
+
+	r := weeklyRange{
+		start: start.Hour()*60 + start.Minute(), //nolint:mnd
+		end:   end.Hour()*60 + end.Minute(),     //nolint:mnd
+	}
+
+	if r.end <= r.start {
+		r.end += minutesPerDay
+	}
+
+	return r, nil
+}
+
+// mergeWeeklyRanges joins the overlapping and adjacent ranges, including the ranges adjacent across the end
+// of the week. It reports whether the ranges cover the whole week.
+func mergeWeeklyRanges(ranges []weeklyRange) ([]weeklyRange, bool) {
+	if len(ranges) == 0 {
+		return nil, false
+	}
+
+	slices.SortFunc(ranges, func(a, b weeklyRange) int {
+		return a.start - b.start
+	})
+
+	merged := []weeklyRange{ranges[0]}
+
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+
+		if r.start <= last.end {
+			last.end = max(last.end, r.end)
+
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	// the last range reaches the first one of the next week
+	if last := &merged[len(merged)-1]; len(merged) > 1 && last.end >= merged[0].start+minutesPerWeek {
+		last.end = max(last.end, merged[0].end+minutesPerWeek)
+		merged = merged[1:]
+	}
+
+	if len(merged) == 1 && merged[0].end-merged[0].start >= minutesPerWeek {
+		return nil, true
+	}
+
+	return merged, false
+}
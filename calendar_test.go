@@ -0,0 +1,150 @@
+package capitalcom_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+// forexHours are the opening hours of a forex market: from Sunday 22:05 to Friday 21:00 with daily breaks.
+func forexHours() capitalcom.OpeningHours {
+	return capitalcom.OpeningHours{
+		Mon:  []string{"00:00 - 21:00", "21:05 - 00:00"},
+		Tue:  []string{"00:00 - 21:00", "21:05 - 00:00"},
+		Wed:  []string{"00:00 - 21:00", "21:05 - 00:00"},
+		Thu:  []string{"00:00 - 21:00", "21:05 - 00:00"},
+		Fri:  []string{"00:00 - 21:00"},
+		Sat:  []string{},
+		Sun:  []string{"22:05 - 00:00"},
+		Zone: "UTC",
+	}
+}
+
+func TestTradingCalendar_IsOpen(t *testing.T) {
+	t.Parallel()
+
+	underTest, err := forexHours().Calendar()
+	require.NoError(t, err)
+
+	// 2026-06-22 is Monday
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected bool
+	}{
+		{"monday morning", time.Date(2026, 6, 22, 9, 0, 0, 0, time.UTC), true},
+		{"monday break", time.Date(2026, 6, 22, 21, 2, 0, 0, time.UTC), false},
+		{"close is exclusive", time.Date(2026, 6, 22, 21, 0, 0, 0, time.UTC), false},
+		{"open is inclusive", time.Date(2026, 6, 22, 21, 5, 0, 0, time.UTC), true},
+		{"saturday", time.Date(2026, 6, 27, 12, 0, 0, 0, time.UTC), false},
+		{"sunday evening", time.Date(2026, 6, 28, 23, 0, 0, 0, time.UTC), true},
+		{"other time zone", time.Date(2026, 6, 28, 23, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, underTest.IsOpen(tt.time))
+		})
+	}
+}
+
+func TestTradingCalendar_NextOpenAndClose(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := forexHours().Calendar()
+	require.NoError(t, err)
+
+	friday := time.Date(2026, 6, 26, 15, 0, 0, 0, time.UTC)
+
+	// Act
+	nextClose, closeOK := underTest.NextClose(friday)
+	nextOpen, openOK := underTest.NextOpen(friday)
+	closeAfterSunday, _ := underTest.NextClose(nextOpen)
+
+	// Assert
+	require.True(t, closeOK)
+	require.Equal(t, time.Date(2026, 6, 26, 21, 0, 0, 0, time.UTC), nextClose)
+	require.True(t, openOK)
+	require.Equal(t, time.Date(2026, 6, 28, 22, 5, 0, 0, time.UTC), nextOpen)
+	require.Equal(t, time.Date(2026, 6, 29, 21, 0, 0, 0, time.UTC), closeAfterSunday)
+}
+
+func TestTradingCalendar_SessionsHonourZoneAndOvernightRanges(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := capitalcom.OpeningHours{
+		Mon:  []string{"18:00 - 02:00"},
+		Tue:  []string{"18:00 - 02:00"},
+		Zone: "America/New_York",
+	}.Calendar()
+	require.NoError(t, err)
+
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	sessions := slices.Collect(underTest.Sessions(from, from.Add(7*24*time.Hour)))
+
+	// Assert
+	require.Equal(t, []capitalcom.Session{
+		{
+			Open:  time.Date(2026, 6, 22, 22, 0, 0, 0, time.UTC),
+			Close: time.Date(2026, 6, 23, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			Open:  time.Date(2026, 6, 23, 22, 0, 0, 0, time.UTC),
+			Close: time.Date(2026, 6, 24, 6, 0, 0, 0, time.UTC),
+		},
+	}, utcSessions(sessions))
+}
+
+func TestTradingCalendar_AlwaysOpen(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	day := []string{"00:00 - 00:00"}
+
+	underTest, err := capitalcom.OpeningHours{
+		Mon: day, Tue: day, Wed: day, Thu: day, Fri: day, Sat: day, Sun: day,
+	}.Calendar()
+	require.NoError(t, err)
+
+	now := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	_, closeOK := underTest.NextClose(now)
+	_, openOK := underTest.NextOpen(now)
+
+	// Assert
+	require.True(t, underTest.IsOpen(now))
+	require.False(t, closeOK)
+	require.False(t, openOK)
+}
+
+func TestOpeningHours_CalendarReturnsErrorForInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	_, rangeErr := capitalcom.OpeningHours{Mon: []string{"9am to 5pm"}}.Calendar()
+	_, zoneErr := capitalcom.OpeningHours{Zone: "Mars/Olympus_Mons"}.Calendar()
+
+	// Assert
+	require.ErrorIs(t, rangeErr, capitalcom.ErrInvalidOpeningHoursRange)
+	require.ErrorAs(t, rangeErr, &capitalcom.OpeningHoursParsingError{})
+	require.ErrorAs(t, zoneErr, &capitalcom.OpeningHoursParsingError{})
+}
+
+func utcSessions(sessions []capitalcom.Session) []capitalcom.Session {
+	for i := range sessions {
+		sessions[i].Open = sessions[i].Open.UTC()
+		sessions[i].Close = sessions[i].Close.UTC()
+	}
+
+	return sessions
+}
@@ -3,6 +3,7 @@ package capitalcom
 import (
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -12,6 +13,8 @@ const (
 
 	APIPathV1 = "/api/v1"
 
+	StreamingHost = "wss://api-streaming-capital.backend-capital.com"
+
 	HeaderAPIKey           = "X-CAP-API-KEY" //nolint:gosec
 	HeaderKeySecurityToken = "X-SECURITY-TOKEN"
 	HeaderTokenCST         = "CST"
@@ -21,12 +24,28 @@ const (
 	dateFormat = "2006-01-02T15:04:05"
 )
 
+// tokens holds the session state, it is safe for concurrent use.
 type tokens struct {
+	mu sync.RWMutex
+
 	securityToken string
 	cst           string
+
+	// passwordIsEncrypted remembers how the current session has been created, so it can be renewed the same way.
+	passwordIsEncrypted bool
+
+	// streamingHost is the streaming API host returned on the session creation.
+	streamingHost string
+
+	// accountID is the account switched to with SwitchActiveAccount, it is switched to again when the session
+	// is renewed as a new session starts on the default account.
+	accountID string
 }
 
 func (t *tokens) headers() http.Header {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
 	headers := make(http.Header)
 	headers.Set(HeaderKeySecurityToken, t.securityToken) //nolint:canonicalheader
 	headers.Set(HeaderTokenCST, t.cst)                   //nolint:canonicalheader
@@ -35,20 +54,112 @@ func (t *tokens) headers() http.Header {
 }
 
 func (t *tokens) updateTokens(res *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.securityToken = res.Header.Get(HeaderKeySecurityToken) //nolint:canonicalheader
 	t.cst = res.Header.Get(HeaderTokenCST)                   //nolint:canonicalheader
 }
 
-// Client Capital.com API client.
+// refreshHeaders replaces the session tokens in the provided headers with the current ones.
+func (t *tokens) refreshHeaders(headers http.Header) http.Header {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	refreshed := headers.Clone()
+	refreshed.Set(HeaderKeySecurityToken, t.securityToken) //nolint:canonicalheader
+	refreshed.Set(HeaderTokenCST, t.cst)                   //nolint:canonicalheader
+
+	return refreshed
+}
+
+func (t *tokens) sessionTokens() (string, string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.cst, t.securityToken
+}
+
+func (t *tokens) setSessionTokens(cst, securityToken string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cst = cst
+	t.securityToken = securityToken
+}
+
+func (t *tokens) currentCST() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.cst
+}
+
+func (t *tokens) isPasswordEncrypted() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.passwordIsEncrypted
+}
+
+func (t *tokens) setPasswordEncrypted(passwordIsEncrypted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.passwordIsEncrypted = passwordIsEncrypted
+}
+
+func (t *tokens) activeAccountID() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.accountID
+}
+
+func (t *tokens) setActiveAccountID(accountID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.accountID = accountID
+}
+
+func (t *tokens) currentStreamingHost() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.streamingHost
+}
+
+func (t *tokens) setStreamingHost(streamingHost string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.streamingHost = streamingHost
+}
+
+// Client Capital.com API client. It is safe for concurrent use by multiple goroutines.
 type Client struct {
 	apiKey     string
 	identifier string
 	password   string
 
-	httpClient *http.Client
-	host       string
-	apiPath    string
-	logger     *slog.Logger
+	httpClient    *http.Client
+	host          string
+	apiPath       string
+	streamingHost string
+	logger        *slog.Logger
+
+	redactedFields []string
+	redactor       *redactor
+
+	requestLogSuccessLevel slog.Level
+	requestLogFailureLevel slog.Level
+
+	autoReauthentication bool
+	reauthenticationMu   sync.Mutex
+
+	rateLimiter *rateLimiter
+	retryPolicy *RetryPolicy
 
 	tokens *tokens
 }
@@ -82,6 +193,14 @@ func WithAPIPath(apiPath string) ClientOption {
 	}
 }
 
+// WithStreamingHost sets the streaming API host for the client.
+// By default the host returned on the session creation is used.
+func WithStreamingHost(streamingHost string) ClientOption {
+	return func(c *Client) {
+		c.streamingHost = streamingHost
+	}
+}
+
 // WithLogger sets the logger for the client.
 func WithLogger(logger *slog.Logger) ClientOption {
 	return func(c *Client) {
@@ -89,6 +208,15 @@ func WithLogger(logger *slog.Logger) ClientOption {
 	}
 }
 
+// WithAutoReauthentication enables or disables the automatic session renewal.
+// When enabled (default) and a request fails because the session has expired,
+// the client creates a new session the same way it has been created originally and replays the request once.
+func WithAutoReauthentication(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.autoReauthentication = enabled
+	}
+}
+
 // NewClient creates a new Capital.com API client.
 func NewClient(apiKey, identifier, password string, opts ...ClientOption) *Client {
 	c := &Client{
@@ -100,12 +228,19 @@ func NewClient(apiKey, identifier, password string, opts ...ClientOption) *Clien
 		apiPath:    APIPathV1,
 		logger:     slog.Default(),
 		tokens:     &tokens{},
+
+		autoReauthentication: true,
+
+		requestLogSuccessLevel: slog.LevelDebug,
+		requestLogFailureLevel: slog.LevelDebug,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	c.redactor = newRedactor(c.redactedFields)
+
 	return c
 }
 
@@ -154,6 +289,13 @@ func (c *Client) Watchlists() *watchlists { //nolint:revive
 	return &watchlists{Client: c}
 }
 
+// Stream creates a new connection handle to the streaming API, see Stream.Connect. The connections use
+// the proxy, the TLS configuration and the dial function of the HTTP client's transport if it is
+// an *http.Transport, other transports aren't used for streaming.
+func (c *Client) Stream(opts ...StreamOption) *Stream {
+	return newStream(c, opts...)
+}
+
 func (c *Client) path(resourcePath string) string {
 	return c.host + c.apiPath + resourcePath
 }
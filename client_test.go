@@ -0,0 +1,121 @@
+package capitalcom_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var sessionsCreated atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == capitalcom.APIPathV1+"/session" {
+			sessionsCreated.Add(1)
+			handleSessionCreation(w, r)
+
+			return
+		}
+
+		if !isAuthorized(w, r) {
+			return
+		}
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/positions":
+			_, _ = w.Write([]byte(`{"positions":[]}`))
+		case capitalcom.APIPathV1 + "/workingorders":
+			_, _ = w.Write([]byte(`{"workingOrders":[]}`))
+		case capitalcom.APIPathV1 + "/markets":
+			_, _ = w.Write([]byte(`{"markets":[]}`))
+		case capitalcom.APIPathV1 + "/prices/BTCUSD":
+			_, _ = w.Write([]byte(`{"prices":[],"instrumentType":"CRYPTOCURRENCIES"}`))
+		case capitalcom.APIPathV1 + "/ping":
+			_, _ = w.Write([]byte(`{"status":"OK"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	calls := []func() error{
+		func() error {
+			_, err := underTest.Positions().List(ctx)
+
+			return err
+		},
+		func() error {
+			_, err := underTest.Orders().List(ctx)
+
+			return err
+		},
+		func() error {
+			_, err := underTest.Markets().Details(ctx, capitalcom.DetailsParams{SearchTerm: "bitcoin"})
+
+			return err
+		},
+		func() error {
+			_, err := underTest.Prices().History(ctx, "BTCUSD", capitalcom.PricesParams{})
+
+			return err
+		},
+		func() error {
+			_, err := underTest.Ping(ctx)
+
+			return err
+		},
+		func() error {
+			_, err := underTest.Session().CreateNew(ctx, false)
+
+			return err
+		},
+	}
+
+	const iterations = 20
+
+	var wg sync.WaitGroup
+
+	// Act
+	for _, call := range calls {
+		for range iterations {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				assert.NoError(t, call())
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	// Assert
+	require.Equal(t, int32(iterations+1), sessionsCreated.Load())
+}
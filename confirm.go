@@ -0,0 +1,177 @@
+package capitalcom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	DealStatusAccepted = "ACCEPTED"
+	DealStatusRejected = "REJECTED"
+)
+
+const (
+	defaultConfirmTimeout    = 10 * time.Second
+	defaultConfirmMinBackoff = 100 * time.Millisecond
+	defaultConfirmMaxBackoff = time.Second
+)
+
+type confirmConfig struct {
+	timeout    time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// ConfirmOption is a functional for setting the config option for a deal confirmation polling.
+type ConfirmOption func(*confirmConfig)
+
+// WithConfirmTimeout sets how long to wait for a deal confirmation, 10 seconds by default.
+// A non-positive timeout is ignored.
+func WithConfirmTimeout(timeout time.Duration) ConfirmOption {
+	return func(c *confirmConfig) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// WithConfirmBackoff sets the delay range between confirmation requests,
+// the delay starts from minDelay and doubles after every attempt up to maxDelay.
+// Non-positive delays are ignored, maxDelay is raised to minDelay if it is lower.
+func WithConfirmBackoff(minDelay, maxDelay time.Duration) ConfirmOption {
+	return func(c *confirmConfig) {
+		if minDelay > 0 {
+			c.minBackoff = minDelay
+		}
+
+		if maxDelay > 0 {
+			c.maxBackoff = maxDelay
+		}
+
+		c.maxBackoff = max(c.maxBackoff, c.minBackoff)
+	}
+}
+
+// WaitForConfirmation polls the confirmation of the deal until it is available.
+// A DealRejectedError is returned if the deal has been rejected.
+func (t *trading) WaitForConfirmation(ctx context.Context, dealReference string, opts ...ConfirmOption) (*Deal, error) {
+	cfg := confirmConfig{
+		timeout:    defaultConfirmTimeout,
+		minBackoff: defaultConfirmMinBackoff,
+		maxBackoff: defaultConfirmMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	delay := cfg.minBackoff
+
+	for {
+		deal, err := t.Confirm(ctx, dealReference)
+		if err == nil {
+			if deal.DealStatus == DealStatusRejected {
+				return deal, NewDealRejectedError(deal)
+			}
+
+			return deal, nil
+		}
+
+		if !isConfirmationPending(ctx, err) {
+			return nil, NewDealConfirmationError(dealReference, err)
+		}
+
+		// the last error tells why the confirmation hasn't been received in time
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return nil, NewDealConfirmationError(dealReference, errors.Join(sleepErr, err))
+		}
+
+		delay = min(delay*2, cfg.maxBackoff) //nolint:mnd
+	}
+}
+
+// isConfirmationPending reports whether the confirmation might become available later.
+func isConfirmationPending(ctx context.Context, err error) bool {
+	var apiErr APIError
+
+	if errors.As(err, &apiErr) && apiErr.StatusCode() == http.StatusNotFound {
+		return true
+	}
+
+	return IsRetryable(err) && ctx.Err() == nil
+}
+
+// OpenAndConfirm opens a new position and waits for the deal confirmation, see Trading.WaitForConfirmation.
+func (p *positions) OpenAndConfirm(ctx context.Context, req OpenPositionRequest, opts ...ConfirmOption) (*Deal, error) {
+	dealReference, err := p.Open(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Trading().WaitForConfirmation(ctx, dealReference, opts...)
+}
+
+// UpdateAndConfirm updates the position and waits for the deal confirmation, see Trading.WaitForConfirmation.
+func (p *positions) UpdateAndConfirm(
+	ctx context.Context,
+	dealID string,
+	req UpdatePositionRequest,
+	opts ...ConfirmOption,
+) (*Deal, error) {
+	dealReference, err := p.Update(ctx, dealID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Trading().WaitForConfirmation(ctx, dealReference, opts...)
+}
+
+// CloseAndConfirm closes the position and waits for the deal confirmation, see Trading.WaitForConfirmation.
+func (p *positions) CloseAndConfirm(ctx context.Context, dealID string, opts ...ConfirmOption) (*Deal, error) {
+	dealReference, err := p.close(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Trading().WaitForConfirmation(ctx, dealReference, opts...)
+}
+
+// CreateAndConfirm creates a working order and waits for the deal confirmation, see Trading.WaitForConfirmation.
+func (o *orders) CreateAndConfirm(ctx context.Context, req CreateOrderRequest, opts ...ConfirmOption) (*Deal, error) {
+	dealReference, err := o.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.Trading().WaitForConfirmation(ctx, dealReference, opts...)
+}
+
+// UpdateAndConfirm updates the working order and waits for the deal confirmation, see Trading.WaitForConfirmation.
+func (o *orders) UpdateAndConfirm(
+	ctx context.Context,
+	dealID string,
+	req UpdateOrderRequest,
+	opts ...ConfirmOption,
+) (*Deal, error) {
+	dealReference, err := o.Update(ctx, dealID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.Trading().WaitForConfirmation(ctx, dealReference, opts...)
+}
+
+// DeleteAndConfirm deletes the working order and waits for the deal confirmation, see Trading.WaitForConfirmation.
+func (o *orders) DeleteAndConfirm(ctx context.Context, dealID string, opts ...ConfirmOption) (*Deal, error) {
+	dealReference, err := o.Delete(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.Trading().WaitForConfirmation(ctx, dealReference, opts...)
+}
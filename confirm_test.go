@@ -0,0 +1,207 @@
+package capitalcom_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+// newConfirmingClient serves deal confirmations after the given number of not found responses.
+func newConfirmingClient(t *testing.T, notFound int32, confirmation string) (*capitalcom.Client, *atomic.Int32) {
+	t.Helper()
+
+	confirmCalls := &atomic.Int32{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/positions", capitalcom.APIPathV1 + "/workingorders":
+			_, _ = w.Write([]byte(`{"dealReference":"o_12345"}`))
+		case capitalcom.APIPathV1 + "/confirms/o_12345":
+			if confirmCalls.Add(1) <= notFound {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"errorCode":"error.not-found.dealReference"}`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(confirmation))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	return client, confirmCalls
+}
+
+func TestPositions_OpenAndConfirm(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, confirmCalls := newConfirmingClient(t, 2, `{
+		"date": "2022-04-06T07:32:19",
+		"status": "OPEN",
+		"dealStatus": "ACCEPTED",
+		"epic": "SILVER",
+		"dealReference": "o_12345",
+		"dealId": "006011e7-0001-54c4-0000-000080560043",
+		"level": 24.285,
+		"size": 1,
+		"direction": "BUY"
+	}`)
+
+	// Act
+	deal, err := underTest.Positions().OpenAndConfirm(context.Background(), capitalcom.OpenPositionRequest{
+		Direction: capitalcom.PositionDirectionBuy,
+		Epic:      "SILVER",
+		Size:      1,
+	}, capitalcom.WithConfirmBackoff(time.Millisecond, 5*time.Millisecond))
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, capitalcom.DealStatusAccepted, deal.DealStatus)
+	require.Equal(t, "006011e7-0001-54c4-0000-000080560043", deal.DealID)
+	require.Equal(t, int32(3), confirmCalls.Load())
+}
+
+func TestOrders_CreateAndConfirmReturnsRejectedDeal(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, _ := newConfirmingClient(t, 0, `{
+		"date": "2022-04-06T07:32:19",
+		"status": "OPEN",
+		"dealStatus": "REJECTED",
+		"rejectReason": "INSUFFICIENT_FUNDS",
+		"epic": "SILVER",
+		"dealReference": "o_12345"
+	}`)
+
+	var rejectedErr capitalcom.DealRejectedError
+
+	// Act
+	deal, err := underTest.Orders().CreateAndConfirm(context.Background(), capitalcom.CreateOrderRequest{
+		Direction: capitalcom.PositionDirectionBuy,
+		Epic:      "SILVER",
+		Size:      1,
+		Type:      capitalcom.LimitOrder,
+		UpdateOrderRequest: capitalcom.UpdateOrderRequest{
+			Level: 20,
+		},
+	})
+
+	// Assert
+	require.ErrorAs(t, err, &rejectedErr)
+	require.Equal(t, "INSUFFICIENT_FUNDS", rejectedErr.Reason())
+	require.Equal(t, deal, rejectedErr.Deal())
+}
+
+func TestTrading_WaitForConfirmationTimesOut(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, _ := newConfirmingClient(t, 1000, "")
+
+	var confirmationErr capitalcom.DealConfirmationError
+
+	// Act
+	_, err := underTest.Trading().WaitForConfirmation(context.Background(), "o_12345",
+		capitalcom.WithConfirmTimeout(50*time.Millisecond),
+		capitalcom.WithConfirmBackoff(time.Millisecond, 5*time.Millisecond))
+
+	// Assert
+	require.ErrorAs(t, err, &confirmationErr)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTrading_WaitForConfirmationIgnoresNonPositiveTimeout(t *testing.T) {
+	t.Parallel()
+
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		// Arrange
+		underTest, confirmCalls := newConfirmingClient(t, 2, `{"date":"2022-04-06T07:32:19","dealReference":"o_12345",`+
+			`"dealStatus":"ACCEPTED","status":"OPEN"}`)
+
+		// Act
+		deal, err := underTest.Trading().WaitForConfirmation(context.Background(), "o_12345",
+			capitalcom.WithConfirmTimeout(timeout),
+			capitalcom.WithConfirmBackoff(time.Millisecond, 5*time.Millisecond))
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, capitalcom.DealStatusAccepted, deal.DealStatus)
+		require.Equal(t, int32(3), confirmCalls.Load())
+	}
+}
+
+func TestTrading_WaitForConfirmationIgnoresNonPositiveBackoff(t *testing.T) {
+	t.Parallel()
+
+	for _, delay := range []time.Duration{0, -time.Second} {
+		// Arrange
+		underTest, confirmCalls := newConfirmingClient(t, 1000, "")
+
+		// Act
+		_, err := underTest.Trading().WaitForConfirmation(context.Background(), "o_12345",
+			capitalcom.WithConfirmTimeout(50*time.Millisecond),
+			capitalcom.WithConfirmBackoff(delay, delay))
+
+		// Assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// the default delay of 100ms outlasts the timeout
+		require.Equal(t, int32(1), confirmCalls.Load())
+	}
+}
+
+func TestTrading_WaitForConfirmationRetriesHTTPClientTimeouts(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var confirmCalls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// the first request outlives the timeout of the HTTP client
+		if confirmCalls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date":"2022-04-06T07:32:19","dealReference":"o_12345",` +
+			`"dealStatus":"ACCEPTED","status":"OPEN"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	httpClient := srv.Client()
+	httpClient.Timeout = 50 * time.Millisecond
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(httpClient),
+		capitalcom.WithHost(srv.URL))
+
+	// Act
+	deal, err := underTest.Trading().WaitForConfirmation(context.Background(),
+		"o_12345",
+		capitalcom.WithConfirmBackoff(time.Millisecond, 5*time.Millisecond))
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, capitalcom.DealStatusAccepted, deal.DealStatus)
+	require.Equal(t, int32(2), confirmCalls.Load())
+}
@@ -0,0 +1,123 @@
+package capitalcom
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrorCode is an error code returned by the Capital.com API. Some codes are followed by a value,
+// e.g. "error.invalid.stoploss.minvalue: 1.5", such codes match the constant without the value.
+//
+// ErrorCode implements error, so it can be used with errors.Is to check an APIError:
+//
+//	if errors.Is(err, capitalcom.ErrorCodeInvalidSessionToken) { ... }
+type ErrorCode string
+
+// Authentication and session error codes.
+const (
+	ErrorCodeInvalidAPIKey                 ErrorCode = "error.invalid.api.key"
+	ErrorCodeNullAPIKey                    ErrorCode = "error.null.api.key"
+	ErrorCodeInvalidDetails                ErrorCode = "error.invalid.details"
+	ErrorCodeNullClientToken               ErrorCode = "error.null.client.token"
+	ErrorCodeInvalidSessionToken           ErrorCode = "error.invalid.session.token"
+	ErrorCodeSecurityClientTokenMissing    ErrorCode = "error.security.client-token-missing"
+	ErrorCodeSecurityAPIKeyMissing         ErrorCode = "error.security.api-key-missing"
+	ErrorCodeMissingCredentials            ErrorCode = "error.public-api.failure.missing.credentials"
+	ErrorCodeEncryptionRequired            ErrorCode = "error.public-api.failure.encryption.required"
+	ErrorCodeKYCRequired                   ErrorCode = "error.public-api.failure.kyc.required"
+	ErrorCodePendingClient                 ErrorCode = "error.public-api.failure.pending.client"
+	ErrorCodeExceededAccountAllowance      ErrorCode = "error.public-api.exceeded-account-allowance"
+	ErrorCodeExceededAPIKeyAllowance       ErrorCode = "error.public-api.exceeded-api-key-allowance"
+	ErrorCodeExceededAccountTradeAllowance ErrorCode = "error.public-api.exceeded-account-trade-allowance"
+	ErrorCodeTooManyRequests               ErrorCode = "error.too-many.requests"
+)
+
+// Trading error codes.
+const (
+	ErrorCodeInsufficientFunds       ErrorCode = "error.invalid.insufficient.funds"
+	ErrorCodeMarketClosed            ErrorCode = "error.invalid.market.closed"
+	ErrorCodeInvalidStopLossMinValue ErrorCode = "error.invalid.stoploss.minvalue"
+	ErrorCodeInvalidStopLossMaxValue ErrorCode = "error.invalid.stoploss.maxvalue"
+	ErrorCodeInvalidTakeProfitMin    ErrorCode = "error.invalid.takeprofit.minvalue"
+	ErrorCodeInvalidTakeProfitMax    ErrorCode = "error.invalid.takeprofit.maxvalue"
+	ErrorCodeInvalidSizeMinValue     ErrorCode = "error.invalid.size.minvalue"
+	ErrorCodeInvalidSizeMaxValue     ErrorCode = "error.invalid.size.maxvalue"
+	ErrorCodeInvalidLeverage         ErrorCode = "error.invalid.leverage.value"
+	ErrorCodeNotFoundEpic            ErrorCode = "error.not-found.epic"
+	ErrorCodeNotFoundDealID          ErrorCode = "error.not-found.dealId"
+)
+
+// Request parameters error codes.
+const (
+	ErrorCodeInvalidDateRange ErrorCode = "error.invalid.daterange"
+	ErrorCodeInvalidFrom      ErrorCode = "error.invalid.from"
+	ErrorCodeInvalidTo        ErrorCode = "error.invalid.to"
+	ErrorCodeInvalidMax       ErrorCode = "error.invalid.max"
+	ErrorCodePricesNotFound   ErrorCode = "error.prices.not-found"
+)
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Matches reports whether the code returned by the API is the code, ignoring a value following the code.
+func (c ErrorCode) Matches(code string) bool {
+	rest, found := strings.CutPrefix(code, string(c))
+
+	return found && (rest == "" || strings.HasPrefix(rest, ":"))
+}
+
+var authErrorCodes = []ErrorCode{ //nolint:gochecknoglobals
+	ErrorCodeInvalidAPIKey,
+	ErrorCodeNullAPIKey,
+	ErrorCodeInvalidDetails,
+	ErrorCodeNullClientToken,
+	ErrorCodeInvalidSessionToken,
+	ErrorCodeSecurityClientTokenMissing,
+	ErrorCodeSecurityAPIKeyMissing,
+	ErrorCodeMissingCredentials,
+	ErrorCodeEncryptionRequired,
+}
+
+// IsRetryable reports whether the request has failed with a transient error and can be sent again:
+// a network error, including a timeout of the HTTP client, 429 Too Many Requests or a 5xx response.
+// A cancelled request isn't retryable, a request failed after the deadline of its context can't be told
+// from a timeout of the HTTP client, so check the context as well before sending the request again.
+// Note that a non-idempotent request failed with a network error or a 5xx response might have been processed.
+func IsRetryable(err error) bool {
+	var apiErr APIError
+
+	if errors.As(err, &apiErr) {
+		return isTransientStatus(apiErr.StatusCode()) || ErrorCodeTooManyRequests.Matches(apiErr.ErrorCode())
+	}
+
+	return isTransientRequestError(err)
+}
+
+// IsAuthError reports whether the request has been rejected because of invalid credentials
+// or a missing or expired session.
+func IsAuthError(err error) bool {
+	var apiErr APIError
+
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	if apiErr.StatusCode() == http.StatusUnauthorized {
+		return true
+	}
+
+	for _, code := range authErrorCodes {
+		if code.Matches(apiErr.ErrorCode()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsMarketClosed reports whether the request has been rejected because the market is closed.
+func IsMarketClosed(err error) bool {
+	return errors.Is(err, ErrorCodeMarketClosed)
+}
@@ -0,0 +1,153 @@
+package capitalcom_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_IsMatchesErrorCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		errorCode string
+		target    capitalcom.ErrorCode
+		expected  bool
+	}{
+		{
+			name:      "same code",
+			errorCode: "error.invalid.session.token",
+			target:    capitalcom.ErrorCodeInvalidSessionToken,
+			expected:  true,
+		},
+		{
+			name:      "code with a value",
+			errorCode: "error.invalid.stoploss.minvalue: 1.5",
+			target:    capitalcom.ErrorCodeInvalidStopLossMinValue,
+			expected:  true,
+		},
+		{
+			name:      "code with a common prefix",
+			errorCode: "error.invalid.from.date",
+			target:    capitalcom.ErrorCodeInvalidFrom,
+			expected:  false,
+		},
+		{
+			name:      "different code",
+			errorCode: "error.invalid.details",
+			target:    capitalcom.ErrorCodeInvalidSessionToken,
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := fmt.Errorf("wrapped: %w", capitalcom.NewAPIError(http.StatusBadRequest, tt.errorCode))
+
+			require.Equal(t, tt.expected, errors.Is(err, tt.target))
+		})
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		isRetryable    bool
+		isAuthError    bool
+		isMarketClosed bool
+	}{
+		{
+			name:        "too many requests",
+			err:         capitalcom.NewAPIError(http.StatusTooManyRequests, "error.too-many.requests"),
+			isRetryable: true,
+		},
+		{
+			name:        "server error",
+			err:         capitalcom.NewAPIError(http.StatusServiceUnavailable, ""),
+			isRetryable: true,
+		},
+		{
+			name:        "network error",
+			err:         capitalcom.NewHTTPRequestError(errors.New("connection reset by peer")),
+			isRetryable: true,
+		},
+		{
+			name:        "HTTP client timeout",
+			err:         capitalcom.NewHTTPRequestError(fmt.Errorf("client timeout: %w", context.DeadlineExceeded)),
+			isRetryable: true,
+		},
+		{
+			name: "cancelled request",
+			err:  capitalcom.NewHTTPRequestError(context.Canceled),
+		},
+		{
+			name:        "expired session",
+			err:         capitalcom.NewAPIError(http.StatusUnauthorized, "error.invalid.session.token"),
+			isAuthError: true,
+		},
+		{
+			name:        "invalid credentials",
+			err:         capitalcom.NewAPIError(http.StatusBadRequest, "error.invalid.details"),
+			isAuthError: true,
+		},
+		{
+			name:           "market closed",
+			err:            capitalcom.NewAPIError(http.StatusBadRequest, "error.invalid.market.closed"),
+			isMarketClosed: true,
+		},
+		{
+			name: "invalid stop loss",
+			err:  capitalcom.NewAPIError(http.StatusBadRequest, "error.invalid.stoploss.minvalue: 1.5"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.isRetryable, capitalcom.IsRetryable(tt.err))
+			require.Equal(t, tt.isAuthError, capitalcom.IsAuthError(tt.err))
+			require.Equal(t, tt.isMarketClosed, capitalcom.IsMarketClosed(tt.err))
+		})
+	}
+}
+
+func TestClient_ReturnsErrorCodeOfServerError(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"errorCode":"error.too-many.requests"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	var apiErr capitalcom.APIError
+
+	// Act
+	_, err := underTest.Ping(context.Background())
+
+	// Assert
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode())
+	require.Equal(t, capitalcom.ErrorCodeTooManyRequests, apiErr.Code())
+	require.ErrorIs(t, err, capitalcom.ErrorCodeTooManyRequests)
+}
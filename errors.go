@@ -3,11 +3,26 @@ package capitalcom
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	werrors "github.com/gromson/capitalcom/pkg/errors"
 )
 
-var ErrPublicKeyTypeError = errors.New("the provided key is not an RSA public key")
+var (
+	ErrPublicKeyTypeError     = errors.New("the provided key is not an RSA public key")
+	ErrStreamClosed           = errors.New("the stream is closed")
+	ErrStreamAlreadyConnected = errors.New("the stream is already connected")
+	ErrStreamNotConnected     = errors.New("the stream is not connected")
+	ErrGoodTillDateRequired   = errors.New("goodTillDate is required for the GOOD_TILL_DATE time in force")
+	ErrGoodTillDateNotAllowed = errors.New("goodTillDate cannot be set for the GOOD_TILL_CANCELLED time in force")
+	ErrUnknownResolution      = errors.New("unknown resolution")
+	ErrInvalidTimeRange       = errors.New("the beginning of the time range must be before its end")
+
+	ErrInvalidOpeningHoursRange = errors.New("the opening hours range must be in the HH:MM - HH:MM format")
+	ErrUnknownMargin            = errors.New("the margin requirement of the market is unknown")
+	ErrInvalidRisk              = errors.New("the stop distance and the risk percent must be positive")
+	ErrAccountNotFound          = errors.New("account not found")
+)
 
 type RequestPayloadEncodingError struct{ werrors.WrapperError }
 
@@ -39,9 +54,161 @@ func NewPasswordEncodingError(err error) PasswordEncodingError {
 	return PasswordEncodingError{werrors.Wrap(err, "failed to encode password")}
 }
 
+type RateLimitWaitError struct{ werrors.WrapperError }
+
+func NewRateLimitWaitError(err error) RateLimitWaitError {
+	return RateLimitWaitError{werrors.Wrap(err, "interrupted while waiting for the rate limit")}
+}
+
+type RetryWaitError struct{ werrors.WrapperError }
+
+func NewRetryWaitError(err error) RetryWaitError {
+	return RetryWaitError{werrors.Wrap(err, "interrupted while waiting to retry the request")}
+}
+
+type SessionRenewalError struct{ werrors.WrapperError }
+
+func NewSessionRenewalError(err error) SessionRenewalError {
+	return SessionRenewalError{werrors.Wrap(err, "failed to renew the expired session")}
+}
+
+type StreamConnectionError struct{ werrors.WrapperError }
+
+func NewStreamConnectionError(err error) StreamConnectionError {
+	return StreamConnectionError{werrors.Wrap(err, "streaming API connection error")}
+}
+
+type PriceCacheError struct{ werrors.WrapperError }
+
+func NewPriceCacheError(path string, err error) PriceCacheError {
+	return PriceCacheError{werrors.Wrap(err, "price cache file %s error", path)}
+}
+
+type OpeningHoursParsingError struct{ werrors.WrapperError }
+
+func NewOpeningHoursParsingError(value string, err error) OpeningHoursParsingError {
+	return OpeningHoursParsingError{werrors.Wrap(err, "failed to parse the opening hours %q", value)}
+}
+
+type TransactionSizeParsingError struct{ werrors.WrapperError }
+
+func NewTransactionSizeParsingError(reference string, err error) TransactionSizeParsingError {
+	return TransactionSizeParsingError{werrors.Wrap(err, "failed to parse the size of the transaction %s", reference)}
+}
+
+type AccountNotFoundError struct{ werrors.WrapperError }
+
+func NewAccountNotFoundError(accountID string) AccountNotFoundError {
+	return AccountNotFoundError{werrors.Wrap(ErrAccountNotFound, "the current account %s", accountID)}
+}
+
+type DealConfirmationError struct{ werrors.WrapperError }
+
+func NewDealConfirmationError(dealReference string, err error) DealConfirmationError {
+	return DealConfirmationError{werrors.Wrap(err, "failed to confirm the deal %s", dealReference)}
+}
+
+// DealRejectedError is returned when a deal confirmation has the REJECTED status.
+type DealRejectedError struct {
+	deal *Deal
+}
+
+func NewDealRejectedError(deal *Deal) DealRejectedError {
+	return DealRejectedError{deal: deal}
+}
+
+func (e DealRejectedError) Error() string {
+	return fmt.Sprintf("the deal has been rejected, dealReference: %s, reason: %s",
+		e.deal.DealReference, e.deal.RejectReason)
+}
+
+// Deal returns the rejected deal confirmation.
+func (e DealRejectedError) Deal() *Deal {
+	return e.deal
+}
+
+// Reason returns the reject reason.
+func (e DealRejectedError) Reason() string {
+	return e.deal.RejectReason
+}
+
+// ValidationError is returned by Validator when a request breaks the dealing rules.
+type ValidationError struct {
+	violations []Violation
+}
+
+func newValidationError(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return ValidationError{violations: violations}
+}
+
+func (e ValidationError) Error() string {
+	messages := make([]string, 0, len(e.violations))
+
+	for _, violation := range e.violations {
+		messages = append(messages, violation.String())
+	}
+
+	return "the request violates the dealing rules: " + strings.Join(messages, "; ")
+}
+
+// Violations returns all found violations.
+func (e ValidationError) Violations() []Violation {
+	return e.violations
+}
+
+// StreamError is returned when the streaming API responds with a non OK status or rejects subscriptions.
+type StreamError struct {
+	destination string
+	status      string
+	// rejected contains statuses of the rejected subscriptions by epic
+	rejected map[string]string
+}
+
+func NewStreamError(destination, status string, rejected map[string]string) StreamError {
+	return StreamError{
+		destination: destination,
+		status:      status,
+		rejected:    rejected,
+	}
+}
+
+func (e StreamError) Error() string {
+	msg := fmt.Sprintf("streaming API returned an error, destination: %s, status: %s", e.destination, e.status)
+
+	if len(e.rejected) > 0 {
+		msg += fmt.Sprintf(", rejected: %v", e.rejected)
+	}
+
+	return msg
+}
+
+func (e StreamError) Destination() string {
+	return e.destination
+}
+
+func (e StreamError) Status() string {
+	return e.status
+}
+
+func (e StreamError) Rejected() map[string]string {
+	return e.rejected
+}
+
+// APIError is returned when the API responds with a non 200 status code. It is comparable.
 type APIError struct {
 	statusCode int
 	errorCode  string
+
+	method       string
+	resourcePath string
+	requestID    string
+	// body is the beginning of the raw response body, see MaxAPIErrorBodySize,
+	// it is kept as a string for the error to stay comparable
+	body string
 }
 
 func NewAPIError(statusCode int, errorCode string) APIError {
@@ -52,7 +219,21 @@ func NewAPIError(statusCode int, errorCode string) APIError {
 }
 
 func (e APIError) Error() string {
-	return fmt.Sprintf("API returned an error, statusCode: %d, errorCode: %s", e.statusCode, e.errorCode)
+	msg := fmt.Sprintf("API returned an error, statusCode: %d, errorCode: %s", e.statusCode, e.errorCode)
+
+	if e.method != "" {
+		msg += fmt.Sprintf(", request: %s %s", e.method, e.resourcePath)
+	}
+
+	if e.requestID != "" {
+		msg += ", requestId: " + e.requestID
+	}
+
+	if e.errorCode == "" && len(e.body) > 0 {
+		msg += fmt.Sprintf(", body: %q", e.body)
+	}
+
+	return msg
 }
 
 func (e APIError) StatusCode() int {
@@ -62,3 +243,35 @@ func (e APIError) StatusCode() int {
 func (e APIError) ErrorCode() string {
 	return e.errorCode
 }
+
+// Method returns the HTTP method of the failed request.
+func (e APIError) Method() string {
+	return e.method
+}
+
+// ResourcePath returns the resource path of the failed request including the query, e.g. "/positions/{dealId}".
+func (e APIError) ResourcePath() string {
+	return e.resourcePath
+}
+
+// RequestID returns the request ID of the failed request if the API has provided one in the response headers.
+func (e APIError) RequestID() string {
+	return e.requestID
+}
+
+// Body returns the beginning of the raw response body, up to MaxAPIErrorBodySize bytes.
+func (e APIError) Body() []byte {
+	return []byte(e.body)
+}
+
+// Code returns the error code as ErrorCode.
+func (e APIError) Code() ErrorCode {
+	return ErrorCode(e.errorCode)
+}
+
+// Is reports whether the error has the target ErrorCode, e.g. errors.Is(err, ErrorCodeInvalidSessionToken).
+func (e APIError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+
+	return ok && code.Matches(e.errorCode)
+}
@@ -0,0 +1,90 @@
+package capitalcom_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func newErrorResponseClient(t *testing.T, statusCode int, body string) *capitalcom.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req-12345")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+}
+
+func TestAPIError_ContainsRequestContext(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	body := `{"errorCode":"error.not-found.epic"}`
+	underTest := newErrorResponseClient(t, http.StatusNotFound, body)
+
+	var apiErr capitalcom.APIError
+
+	// Act
+	_, err := underTest.Markets().Detail(context.Background(), "UNKNOWN")
+
+	// Assert
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	require.Equal(t, capitalcom.ErrorCodeNotFoundEpic, apiErr.Code())
+	require.Equal(t, http.MethodGet, apiErr.Method())
+	require.Equal(t, "/markets/UNKNOWN", apiErr.ResourcePath())
+	require.Equal(t, "req-12345", apiErr.RequestID())
+	require.JSONEq(t, body, string(apiErr.Body()))
+	require.Contains(t, apiErr.Error(), "request: GET /markets/UNKNOWN")
+}
+
+func TestAPIError_KeepsNonJSONBody(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := newErrorResponseClient(t, http.StatusForbidden, "<html>Forbidden</html>")
+
+	var apiErr capitalcom.APIError
+
+	// Act
+	_, err := underTest.Markets().Detail(context.Background(), "BTCUSD")
+
+	// Assert
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	require.Empty(t, apiErr.ErrorCode())
+	require.Equal(t, "<html>Forbidden</html>", string(apiErr.Body()))
+	require.Contains(t, apiErr.Error(), `body: "<html>Forbidden</html>"`)
+}
+
+func TestAPIError_IsComparable(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := newErrorResponseClient(t, http.StatusNotFound, `{"errorCode":"error.not-found.epic"}`)
+
+	var apiErr capitalcom.APIError
+
+	_, err := underTest.Markets().Detail(context.Background(), "UNKNOWN")
+	require.ErrorAs(t, err, &apiErr)
+
+	// Act
+	seen := map[capitalcom.APIError]bool{apiErr: true}
+
+	// Assert
+	require.True(t, seen[apiErr])
+	require.NotEqual(t, capitalcom.NewAPIError(http.StatusNotFound, "error.not-found.epic"), apiErr)
+	require.True(t, capitalcom.NewAPIError(http.StatusNotFound, "") == capitalcom.NewAPIError(http.StatusNotFound, ""))
+}
@@ -0,0 +1,169 @@
+package capitalcom
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// FundingCharge is an overnight fee charged, or credited if positive, to a position at the time.
+type FundingCharge struct {
+	Time   time.Time
+	Amount float64
+}
+
+// FundingProjection is a projection of the overnight fees of a position for a holding period.
+// The amounts are in the currency of the instrument.
+type FundingProjection struct {
+	Charges  []FundingCharge
+	Total    float64
+	Currency string
+}
+
+// FundingReconciliation compares the projected overnight fees of a position with the swap transactions.
+type FundingReconciliation struct {
+	Projection FundingProjection
+	// Transactions are the swap transactions matching the projected charges
+	Transactions []Transaction
+	// Actual is the sum of the matched transactions
+	Actual float64
+	// Difference is Actual minus the projected total
+	Difference float64
+	// MissingCharges are the projected charges without a matching transaction
+	MissingCharges []FundingCharge
+}
+
+// FundingCalculator projects overnight fees (swaps) of positions from the overnight fee of a market.
+// The rates are percents of the position value charged every swap charge interval, a negative rate
+// is a charge and a positive one is a credit. Charges are projected at a regular interval, additional
+// charges the broker may apply over weekends and holidays aren't known from the rates.
+type FundingCalculator struct {
+	market MarketDetails
+}
+
+// NewFundingCalculator creates a calculator for the market, see Markets.Detail.
+func NewFundingCalculator(market MarketDetails) *FundingCalculator {
+	return &FundingCalculator{market: market}
+}
+
+// ChargeAmount returns the fee applied to the position on every charge, the position value is calculated
+// at the opening level of the position.
+func (c *FundingCalculator) ChargeAmount(position Position) float64 {
+	rate := c.market.Instrument.OvernightFee.LongRate
+	if position.Direction == PositionDirectionSell {
+		rate = c.market.Instrument.OvernightFee.ShortRate
+	}
+
+	return position.Size * position.Level * rate / 100 //nolint:mnd
+}
+
+// NextCharge returns the time of the first charge after the time,
+// it returns false if the market doesn't define the charge schedule.
+func (c *FundingCalculator) NextCharge(t time.Time) (time.Time, bool) {
+	first, interval, ok := c.schedule()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	elapsed := t.Sub(first)
+	charges := elapsed / interval
+
+	// round the division down for the times before the first charge
+	if elapsed < 0 && elapsed%interval != 0 {
+		charges--
+	}
+
+	return first.Add((charges + 1) * interval), true
+}
+
+// Project returns the charges applied to the position after the time from and up to the time to inclusive.
+func (c *FundingCalculator) Project(position Position, from, to time.Time) FundingProjection {
+	projection := FundingProjection{Currency: c.market.Instrument.Currency}
+
+	_, interval, ok := c.schedule()
+	if !ok {
+		return projection
+	}
+
+	amount := c.ChargeAmount(position)
+
+	next, _ := c.NextCharge(from)
+
+	for ; !next.After(to); next = next.Add(interval) {
+		projection.Charges = append(projection.Charges, FundingCharge{Time: next.UTC(), Amount: amount})
+		projection.Total += amount
+	}
+
+	return projection
+}
+
+// Reconcile projects the charges of the position from its creation up to the time and matches them
+// with the swap transactions of the instrument, see Account.TransactionHistory. A transaction matches
+// a charge if it happened within a half of the charge interval from it. The transaction amounts are
+// in the account currency, so the difference is meaningful only if it is the currency of the instrument.
+func (c *FundingCalculator) Reconcile(
+	position Position,
+	transactions []Transaction,
+	until time.Time,
+) (FundingReconciliation, error) {
+	if position.CreatedDateUTC.IsZero() || !position.CreatedDateUTC.Before(until) {
+		return FundingReconciliation{}, ErrInvalidTimeRange
+	}
+
+	reconciliation := FundingReconciliation{
+		Projection: c.Project(position, position.CreatedDateUTC, until),
+	}
+
+	_, interval, _ := c.schedule()
+	tolerance := interval / 2 //nolint:mnd
+
+	matched := make([]bool, len(transactions))
+
+	for _, charge := range reconciliation.Projection.Charges {
+		found := false
+
+		for i, transaction := range transactions {
+			if matched[i] ||
+				transaction.TransactionType != TransactionTypeSwap ||
+				transaction.InstrumentName != c.market.Instrument.Name ||
+				math.Abs(float64(transaction.DateUTC.Sub(charge.Time))) > float64(tolerance) {
+				continue
+			}
+
+			amount, err := strconv.ParseFloat(transaction.Size, 64)
+			if err != nil {
+				return FundingReconciliation{}, NewTransactionSizeParsingError(transaction.Reference, err)
+			}
+
+			matched[i] = true
+			found = true
+
+			reconciliation.Transactions = append(reconciliation.Transactions, transaction)
+			reconciliation.Actual += amount
+
+			break
+		}
+
+		if !found {
+			reconciliation.MissingCharges = append(reconciliation.MissingCharges, charge)
+		}
+	}
+
+	reconciliation.Difference = reconciliation.Actual - reconciliation.Projection.Total
+
+	return reconciliation, nil
+}
+
+// schedule returns the time of a charge and the charge interval, the timestamp is in milliseconds
+// and the interval is in minutes.
+func (c *FundingCalculator) schedule() (time.Time, time.Duration, bool) {
+	fee := c.market.Instrument.OvernightFee
+
+	if fee.SwapChargeTimestamp <= 0 || fee.SwapChargeInterval <= 0 {
+		return time.Time{}, 0, false
+	}
+
+	first := time.UnixMilli(int64(fee.SwapChargeTimestamp)).UTC()
+
+	return first, time.Duration(fee.SwapChargeInterval) * time.Minute, true
+}
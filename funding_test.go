@@ -0,0 +1,166 @@
+package capitalcom_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+// 2026-06-22 21:00 UTC
+const swapChargeTimestamp = 1782162000000
+
+func fundingMarket() capitalcom.MarketDetails {
+	return capitalcom.MarketDetails{
+		Instrument: capitalcom.Instrument{
+			Name:     "Bitcoin to US Dollar",
+			Currency: "USD",
+			OvernightFee: capitalcom.OvernightFee{
+				LongRate:            -0.05,
+				ShortRate:           0.01,
+				SwapChargeTimestamp: swapChargeTimestamp,
+				SwapChargeInterval:  1440,
+			},
+		},
+	}
+}
+
+func TestFundingCalculator_ChargeAmount(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewFundingCalculator(fundingMarket())
+
+	// Act
+	long := underTest.ChargeAmount(capitalcom.Position{
+		Direction: capitalcom.PositionDirectionBuy,
+		Size:      2,
+		Level:     50000,
+	})
+	short := underTest.ChargeAmount(capitalcom.Position{
+		Direction: capitalcom.PositionDirectionSell,
+		Size:      2,
+		Level:     50000,
+	})
+
+	// Assert
+	require.InDelta(t, -50, long, 1e-9)
+	require.InDelta(t, 10, short, 1e-9)
+}
+
+func TestFundingCalculator_NextCharge(t *testing.T) {
+	t.Parallel()
+
+	underTest := capitalcom.NewFundingCalculator(fundingMarket())
+	charge := time.Date(2026, 6, 22, 21, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected time.Time
+	}{
+		{"before the timestamp", charge.Add(-30 * time.Hour), charge.Add(-24 * time.Hour)},
+		{"at a charge", charge, charge.Add(24 * time.Hour)},
+		{"after the timestamp", charge.Add(50 * time.Hour), charge.Add(72 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, ok := underTest.NextCharge(tt.time)
+
+			require.True(t, ok)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestFundingCalculator_Project(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewFundingCalculator(fundingMarket())
+	position := capitalcom.Position{Direction: capitalcom.PositionDirectionBuy, Size: 1, Level: 10000}
+	from := time.Date(2026, 6, 22, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	actual := underTest.Project(position, from, from.Add(57*time.Hour))
+
+	// Assert
+	require.Equal(t, "USD", actual.Currency)
+	require.InDelta(t, -15, actual.Total, 1e-9)
+	require.Equal(t, []capitalcom.FundingCharge{
+		{Time: time.Date(2026, 6, 22, 21, 0, 0, 0, time.UTC), Amount: -5},
+		{Time: time.Date(2026, 6, 23, 21, 0, 0, 0, time.UTC), Amount: -5},
+		{Time: time.Date(2026, 6, 24, 21, 0, 0, 0, time.UTC), Amount: -5},
+	}, actual.Charges)
+}
+
+func TestFundingCalculator_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewFundingCalculator(fundingMarket())
+	position := capitalcom.Position{
+		Direction:      capitalcom.PositionDirectionBuy,
+		Size:           1,
+		Level:          10000,
+		CreatedDateUTC: time.Date(2026, 6, 22, 12, 0, 0, 0, time.UTC),
+	}
+
+	swap := func(date time.Time, size string) capitalcom.Transaction {
+		return capitalcom.Transaction{
+			DateUTC:         date,
+			InstrumentName:  "Bitcoin to US Dollar",
+			TransactionType: capitalcom.TransactionTypeSwap,
+			Size:            size,
+		}
+	}
+
+	transactions := []capitalcom.Transaction{
+		swap(time.Date(2026, 6, 22, 21, 0, 3, 0, time.UTC), "-5.01"),
+		{
+			DateUTC:         time.Date(2026, 6, 23, 21, 0, 0, 0, time.UTC),
+			InstrumentName:  "Bitcoin to US Dollar",
+			TransactionType: capitalcom.TransactionTypeTrade,
+			Size:            "-100",
+		},
+		swap(time.Date(2026, 6, 24, 21, 0, 1, 0, time.UTC), "-4.99"),
+	}
+
+	// Act
+	actual, err := underTest.Reconcile(position, transactions, time.Date(2026, 6, 25, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	require.NoError(t, err)
+	require.InDelta(t, -15, actual.Projection.Total, 1e-9)
+	require.InDelta(t, -10, actual.Actual, 1e-9)
+	require.InDelta(t, 5, actual.Difference, 1e-9)
+	require.Len(t, actual.Transactions, 2)
+	require.Equal(t, []capitalcom.FundingCharge{
+		{Time: time.Date(2026, 6, 23, 21, 0, 0, 0, time.UTC), Amount: -5},
+	}, actual.MissingCharges)
+}
+
+func TestFundingCalculator_ReconcileReturnsErrorForInvalidSize(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewFundingCalculator(fundingMarket())
+	position := capitalcom.Position{CreatedDateUTC: time.Date(2026, 6, 22, 12, 0, 0, 0, time.UTC)}
+
+	transactions := []capitalcom.Transaction{{
+		DateUTC:         time.Date(2026, 6, 22, 21, 0, 0, 0, time.UTC),
+		InstrumentName:  "Bitcoin to US Dollar",
+		TransactionType: capitalcom.TransactionTypeSwap,
+		Size:            "n/a",
+	}}
+
+	// Act
+	_, err := underTest.Reconcile(position, transactions, time.Date(2026, 6, 23, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	require.ErrorAs(t, err, &capitalcom.TransactionSizeParsingError{})
+}
@@ -0,0 +1,146 @@
+package capitalcom
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"time"
+)
+
+// HistoryWindow is a time window the activity and transaction history is requested by in the iterators,
+// the API limits the activity history range to one day.
+const HistoryWindow = 24 * time.Hour
+
+// HistoryIter lazily retrieves historical prices of the time range, requesting chunks of MaxPricesPerRequest bars
+// as the sequence is consumed. Bars are yielded in order without duplicates. The iteration stops after the
+// first error, which is yielded with a zero Price, or when the context is done.
+func (p *prices) HistoryIter(
+	ctx context.Context,
+	epic string,
+	resolution Resolution,
+	from time.Time,
+	to time.Time,
+) iter.Seq2[Price, error] {
+	return func(yield func(Price, error) bool) {
+		windows, err := historyWindows(resolution, from, to)
+		if err != nil {
+			yield(Price{}, err)
+
+			return
+		}
+
+		// the windows overlap by a bar, the bars up to the last yielded one are skipped
+		var last time.Time
+
+		for _, window := range windows {
+			if err := ctx.Err(); err != nil {
+				yield(Price{}, err)
+
+				return
+			}
+
+			chunk, err := p.historyWindow(ctx, epic, resolution, window)
+			if err != nil {
+				yield(Price{}, err)
+
+				return
+			}
+
+			slices.SortStableFunc(chunk.Prices, func(a, b Price) int {
+				return a.SnapshotTimeUTC.Compare(b.SnapshotTimeUTC)
+			})
+
+			for _, price := range chunk.Prices {
+				if !last.IsZero() && !price.SnapshotTimeUTC.After(last) {
+					continue
+				}
+
+				last = price.SnapshotTimeUTC
+
+				if !yield(price, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ActivityHistoryIter lazily retrieves the activity history between params.From and params.To, which are required,
+// requesting it by HistoryWindow as the sequence is consumed. Activities are yielded in order of DateUTC.
+// The iteration stops after the first error, which is yielded with a zero Activity, or when the context is done.
+func (a *account) ActivityHistoryIter(ctx context.Context, params ActivityParams) iter.Seq2[Activity, error] {
+	return historyIter(ctx, params.From, params.To,
+		func(ctx context.Context, from, to time.Time) ([]Activity, error) {
+			windowParams := params
+			windowParams.From = from
+			windowParams.To = to
+
+			return a.ActivityHistory(ctx, windowParams)
+		},
+		func(a, b Activity) int {
+			return a.DateUTC.Compare(b.DateUTC)
+		})
+}
+
+// TransactionHistoryIter lazily retrieves the transaction history between params.From and params.To,
+// which are required, requesting it by HistoryWindow as the sequence is consumed. Transactions are yielded in order
+// of DateUTC. The iteration stops after the first error, which is yielded with a zero Transaction,
+// or when the context is done.
+func (a *account) TransactionHistoryIter(ctx context.Context, params TransactionParams) iter.Seq2[Transaction, error] {
+	return historyIter(ctx, params.From, params.To,
+		func(ctx context.Context, from, to time.Time) ([]Transaction, error) {
+			windowParams := params
+			windowParams.From = from
+			windowParams.To = to
+
+			return a.TransactionHistory(ctx, windowParams)
+		},
+		func(a, b Transaction) int {
+			return a.DateUTC.Compare(b.DateUTC)
+		})
+}
+
+// historyIter requests the records by HistoryWindow. The windows don't overlap as the API accepts the time
+// range bounds with a precision of a second and both bounds are inclusive.
+func historyIter[T any](
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	fetch func(ctx context.Context, from, to time.Time) ([]T, error),
+	compare func(a, b T) int,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		if from.IsZero() || to.IsZero() || !from.Before(to) {
+			yield(zero, ErrInvalidTimeRange)
+
+			return
+		}
+
+		for start := from; !start.After(to); start = start.Add(HistoryWindow) {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+
+				return
+			}
+
+			end := minTime(start.Add(HistoryWindow-time.Second), to)
+
+			records, err := fetch(ctx, start, end)
+			if err != nil {
+				yield(zero, err)
+
+				return
+			}
+
+			slices.SortStableFunc(records, compare)
+
+			for _, record := range records {
+				if !yield(record, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package capitalcom_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+// newActivityServer serves an activity at the start and at the end of every requested range.
+func newActivityServer(t *testing.T) (*httptest.Server, func() []pricesRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []pricesRequest
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		from, err := time.Parse(pricesDateFormat, query.Get("from"))
+		require.NoError(t, err)
+
+		to, err := time.Parse(pricesDateFormat, query.Get("to"))
+		require.NoError(t, err)
+
+		mu.Lock()
+		requests = append(requests, pricesRequest{from: from, to: to})
+		mu.Unlock()
+
+		activities := make([]map[string]any, 0)
+
+		// the API doesn't guarantee the order
+		for _, date := range []time.Time{to, from} {
+			activities = append(activities, map[string]any{
+				"date":    date.Format(pricesDateFormat),
+				"dateUTC": date.Format(pricesDateFormat),
+				"epic":    "BTCUSD",
+				"dealId":  date.Format(time.RFC3339),
+				"source":  "USER",
+				"type":    "POSITION",
+				"status":  "ACCEPTED",
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"activities": activities})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []pricesRequest {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+}
+
+func TestPrices_HistoryIter(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2500 * time.Minute)
+	srv, requests := newMinutePricesServer(t, time.Time{}, time.Time{})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	// Act
+	var actual []capitalcom.Price
+
+	seq := underTest.Prices().HistoryIter(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, to)
+
+	for price, err := range seq {
+		require.NoError(t, err)
+
+		actual = append(actual, price)
+	}
+
+	// Assert
+	require.Len(t, actual, 2501)
+
+	for i, price := range actual {
+		require.Equal(t, from.Add(time.Duration(i)*time.Minute), price.SnapshotTimeUTC)
+	}
+
+	require.Len(t, requests(), 3)
+}
+
+func TestPrices_HistoryIterStopsRequestingWhenConsumerBreaks(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2500 * time.Minute)
+	srv, requests := newMinutePricesServer(t, time.Time{}, time.Time{})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	// Act
+	count := 0
+
+	seq := underTest.Prices().HistoryIter(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, to)
+
+	for _, err := range seq {
+		require.NoError(t, err)
+
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	// Assert
+	require.Equal(t, 10, count)
+	require.Len(t, requests(), 1)
+}
+
+func TestAccount_ActivityHistoryIter(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	to := from.Add(60 * time.Hour)
+	srv, requests := newActivityServer(t)
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	// Act
+	var actual []time.Time
+
+	params := capitalcom.ActivityParams{From: from, To: to}
+
+	for activity, err := range underTest.Account().ActivityHistoryIter(context.Background(), params) {
+		require.NoError(t, err)
+
+		actual = append(actual, activity.DateUTC)
+	}
+
+	// Assert
+	require.Equal(t, []pricesRequest{
+		{from: from, to: from.Add(capitalcom.HistoryWindow - time.Second)},
+		{from: from.Add(capitalcom.HistoryWindow), to: from.Add(2*capitalcom.HistoryWindow - time.Second)},
+		{from: from.Add(2 * capitalcom.HistoryWindow), to: to},
+	}, requests())
+
+	require.Equal(t, []time.Time{
+		from,
+		from.Add(capitalcom.HistoryWindow - time.Second),
+		from.Add(capitalcom.HistoryWindow),
+		from.Add(2*capitalcom.HistoryWindow - time.Second),
+		from.Add(2 * capitalcom.HistoryWindow),
+		to,
+	}, actual)
+}
+
+func TestAccount_ActivityHistoryIterStopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	srv, requests := newActivityServer(t)
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	params := capitalcom.ActivityParams{From: from, To: from.Add(72 * time.Hour)}
+
+	// Act
+	var (
+		count   int
+		lastErr error
+	)
+
+	for _, err := range underTest.Account().ActivityHistoryIter(ctx, params) {
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		count++
+
+		cancel()
+	}
+
+	// Assert
+	require.ErrorIs(t, lastErr, context.Canceled)
+	require.Equal(t, 2, count)
+	require.Len(t, requests(), 1)
+}
+
+func TestAccount_TransactionHistoryIterReturnsErrorForInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewClient(expectedAPIKey, identifier, password)
+
+	// Act
+	var errs []error
+
+	for _, err := range underTest.Account().TransactionHistoryIter(context.Background(), capitalcom.TransactionParams{}) {
+		errs = append(errs, err)
+	}
+
+	// Assert
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], capitalcom.ErrInvalidTimeRange)
+}
+
+func TestPrices_HistoryIterReturnsErrorForUnknownEpic(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := newErrorResponseClient(t, http.StatusNotFound, `{"errorCode":"error.not-found.epic"}`)
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	var errs []error
+
+	seq := underTest.Prices().HistoryIter(context.Background(), "UNKNOWN", capitalcom.ResolutionMinute, from,
+		from.Add(time.Hour))
+
+	for _, err := range seq {
+		errs = append(errs, err)
+	}
+
+	// Assert
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], capitalcom.ErrorCodeNotFoundEpic)
+}
@@ -8,8 +8,15 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httputil"
+	"time"
 )
 
+// MaxAPIErrorBodySize is the maximum number of bytes of an error response body kept in APIError.
+const MaxAPIErrorBodySize = 4 << 10
+
+// requestIDHeaders are headers checked for a request ID of a failed request.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Requestid"} //nolint:gochecknoglobals
+
 type response[TResPayload any] struct {
 	httpResponse *http.Response
 	payload      *TResPayload
@@ -67,14 +74,14 @@ func del[TResPayload any](
 	return doRequest[TResPayload](ctx, c, http.MethodDelete, resourcePath, nil, headers)
 }
 
-func prepareRequestBody(payload any) (*bytes.Buffer, error) {
+func prepareRequestBody(payload any) ([]byte, error) {
 	reqBody := new(bytes.Buffer)
 
 	if err := json.NewEncoder(reqBody).Encode(payload); err != nil {
 		return nil, NewRequestPayloadEncodingError(err)
 	}
 
-	return reqBody, nil
+	return reqBody.Bytes(), nil
 }
 
 func doRequest[TResPayload any](
@@ -82,38 +89,62 @@ func doRequest[TResPayload any](
 	c *Client,
 	method string,
 	resourcePath string,
-	reqBody io.Reader,
+	reqBody []byte,
 	headers http.Header,
 ) (*response[TResPayload], error) {
-	req, err := http.NewRequestWithContext(
-		ctx,
-		method,
-		c.path(resourcePath),
-		reqBody)
-	if err != nil {
-		return nil, NewRequestCreationError(err)
+	start := time.Now()
+
+	res, err := exchange[TResPayload](ctx, c, method, resourcePath, reqBody, headers)
+
+	result := requestResult{
+		method:       method,
+		resourcePath: resourcePath,
+		reqBody:      reqBody,
+		latency:      time.Since(start),
+		err:          err,
 	}
 
-	setRequestHeaders(req, headers)
+	if res != nil {
+		result.res = res.httpResponse
+		result.resPayload = res.payload
+	}
 
-	logRequest(ctx, req, c.logger)
+	c.logRequestResult(ctx, result)
 
-	res, err := c.httpClient.Do(req)
+	return res, err
+}
+
+// exchange sends the request, renewing the session if it has expired, and decodes the response payload.
+func exchange[TResPayload any](
+	ctx context.Context,
+	c *Client,
+	method string,
+	resourcePath string,
+	reqBody []byte,
+	headers http.Header,
+) (*response[TResPayload], error) {
+	res, err := sendRequestWithRetry(ctx, c, method, resourcePath, reqBody, headers)
 	if err != nil {
-		return nil, NewHTTPRequestError(err)
+		return nil, err
 	}
 
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			c.logger.With("error", err).
-				Error("failed to close a response body")
+	if isSessionExpired(res) && c.canReauthenticate(ctx, headers) {
+		closeResponseBody(res, c.logger)
+
+		if err := c.reauthenticate(ctx, headers.Get(HeaderTokenCST)); err != nil { //nolint:canonicalheader
+			return nil, err
 		}
-	}()
 
-	logResponse(ctx, res, c.logger)
+		res, err = sendRequestWithRetry(ctx, c, method, resourcePath, reqBody, c.tokens.refreshHeaders(headers))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defer closeResponseBody(res, c.logger)
 
 	if res.StatusCode != http.StatusOK {
-		return nil, handleErrorResponse(res)
+		return nil, handleErrorResponse(res, method, resourcePath)
 	}
 
 	resPayload := new(TResPayload)
@@ -128,6 +159,81 @@ func doRequest[TResPayload any](
 	}, nil
 }
 
+func sendRequest(
+	ctx context.Context,
+	c *Client,
+	method string,
+	resourcePath string,
+	reqBody []byte,
+	headers http.Header,
+) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx, method, resourcePath); err != nil {
+			return nil, err
+		}
+	}
+
+	var body io.Reader
+
+	if reqBody != nil {
+		body = bytes.NewReader(reqBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.path(resourcePath), body)
+	if err != nil {
+		return nil, NewRequestCreationError(err)
+	}
+
+	setRequestHeaders(req, headers)
+
+	logRequest(ctx, req, c.logger, c.redactor)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, NewHTTPRequestError(err)
+	}
+
+	logResponse(ctx, res, c.logger, c.redactor)
+
+	return res, nil
+}
+
+func closeResponseBody(res *http.Response, logger *slog.Logger) {
+	if err := res.Body.Close(); err != nil {
+		logger.With("error", err).
+			Error("failed to close a response body")
+	}
+}
+
+// isSessionExpired reports whether the API has rejected the session tokens of the request: a 401 response
+// or an error response with the invalid session token code. The body of the response stays readable.
+func isSessionExpired(res *http.Response) bool {
+	if res.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+
+	if res.StatusCode == http.StatusOK {
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, MaxAPIErrorBodySize))
+	res.Body = replayedBody{Reader: io.MultiReader(bytes.NewReader(body), res.Body), Closer: res.Body}
+
+	if err != nil {
+		return false
+	}
+
+	errPayload := &errorResponsePayload{}
+
+	return json.Unmarshal(body, errPayload) == nil && ErrorCodeInvalidSessionToken.Matches(errPayload.ErrorCode)
+}
+
+// replayedBody is a response body with the already read beginning put back.
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
 func setRequestHeaders(req *http.Request, headers http.Header) {
 	req.Header.Set("Content-Type", "application/json")
 
@@ -138,21 +244,43 @@ func setRequestHeaders(req *http.Request, headers http.Header) {
 	}
 }
 
-func handleErrorResponse(res *http.Response) error {
-	if res.StatusCode < http.StatusBadRequest || res.StatusCode >= http.StatusInternalServerError {
-		return NewAPIError(res.StatusCode, "")
+func handleErrorResponse(res *http.Response, method, resourcePath string) error {
+	apiErr := APIError{
+		statusCode:   res.StatusCode,
+		method:       method,
+		resourcePath: resourcePath,
+		requestID:    requestID(res.Header),
 	}
 
+	body, err := io.ReadAll(io.LimitReader(res.Body, MaxAPIErrorBodySize))
+	if err != nil {
+		return NewHTTPRequestError(err)
+	}
+
+	apiErr.body = string(body)
+
 	errPayload := &errorResponsePayload{}
 
-	if err := json.NewDecoder(res.Body).Decode(errPayload); err != nil {
-		return NewResponsePayloadDecodingError(err)
+	// the body is not guaranteed to be a JSON, e.g. when the error comes from a proxy, then the error code is empty
+	if err := json.Unmarshal(body, errPayload); err == nil {
+		apiErr.errorCode = errPayload.ErrorCode
+	}
+
+	return apiErr
+}
+
+// requestID returns the first found request ID header value.
+func requestID(header http.Header) string {
+	for _, key := range requestIDHeaders {
+		if id := header.Get(key); id != "" {
+			return id
+		}
 	}
 
-	return NewAPIError(res.StatusCode, errPayload.ErrorCode)
+	return ""
 }
 
-func logRequest(ctx context.Context, req *http.Request, logger *slog.Logger) {
+func logRequest(ctx context.Context, req *http.Request, logger *slog.Logger, redactor *redactor) {
 	if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
 		return
 	}
@@ -164,10 +292,10 @@ func logRequest(ctx context.Context, req *http.Request, logger *slog.Logger) {
 		return
 	}
 
-	logger.With("request", dump).Debug("sending a request")
+	logger.With("request", redactor.redact(dump)).Debug("sending a request")
 }
 
-func logResponse(ctx context.Context, res *http.Response, logger *slog.Logger) {
+func logResponse(ctx context.Context, res *http.Response, logger *slog.Logger, redactor *redactor) {
 	if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
 		return
 	}
@@ -179,5 +307,5 @@ func logResponse(ctx context.Context, res *http.Response, logger *slog.Logger) {
 		return
 	}
 
-	logger.With("response", dump).Debug("received a response")
+	logger.With("response", redactor.redact(dump)).Debug("received a response")
 }
@@ -0,0 +1,210 @@
+// Package websocket implements a minimal WebSocket (RFC 6455) connection covering what the Capital.com
+// streaming API needs: text and binary messages, ping/pong and close frames. It has no extensions support.
+package websocket
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gromson/capitalcom/internal/wsproto"
+	werrors "github.com/gromson/capitalcom/pkg/errors"
+)
+
+// MessageType is a type of data message.
+type MessageType int
+
+const (
+	TextMessage   MessageType = 1
+	BinaryMessage MessageType = 2
+)
+
+// Close status codes.
+const (
+	CloseNormalClosure    = 1000
+	CloseGoingAway        = 1001
+	CloseProtocolError    = 1002
+	CloseNoStatusReceived = wsproto.CloseNoStatusReceived
+)
+
+const (
+	// MaxMessageSize is the maximum size of a message the connection reads.
+	MaxMessageSize = 16 << 20
+
+	closeWriteTimeout = time.Second
+)
+
+var (
+	ErrBadHandshake    = errors.New("websocket: bad handshake")
+	ErrProtocol        = wsproto.ErrProtocol
+	ErrMessageTooLarge = wsproto.ErrMessageTooLarge
+	ErrClosed          = errors.New("websocket: connection is closed")
+)
+
+// CloseError is returned by ReadMessage when the peer closes the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e CloseError) Error() string {
+	return "websocket: connection closed by peer, code: " + strconv.Itoa(e.Code) + ", reason: " + e.Reason
+}
+
+// Conn is a client WebSocket connection, see Dial. ReadMessage must not be called concurrently,
+// writes are safe to be used concurrently.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+	closed  bool
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newConn(conn net.Conn, reader *bufio.Reader) *Conn {
+	return &Conn{
+		conn:   conn,
+		reader: reader,
+	}
+}
+
+// ReadMessage reads the next data message. Ping frames are answered automatically.
+// A CloseError is returned when the peer has closed the connection.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	var (
+		messageType MessageType
+		message     []byte
+	)
+
+	for {
+		fin, op, payload, err := wsproto.ReadFrame(c.reader, MaxMessageSize)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case wsproto.OpPing:
+			if err := c.writeFrame(wsproto.OpPong, payload); err != nil && !errors.Is(err, ErrClosed) {
+				return 0, nil, err
+			}
+
+			continue
+		case wsproto.OpPong:
+			continue
+		case wsproto.OpClose:
+			return 0, nil, c.handleClose(payload)
+		case wsproto.OpText, wsproto.OpBinary:
+			if messageType != 0 {
+				return 0, nil, werrors.Wrap(ErrProtocol, "a new message started before the previous one finished")
+			}
+
+			messageType = MessageType(op)
+		case wsproto.OpContinuation:
+			if messageType == 0 {
+				return 0, nil, werrors.Wrap(ErrProtocol, "unexpected continuation frame")
+			}
+		default:
+			return 0, nil, werrors.Wrap(ErrProtocol, "unknown opcode %d", op)
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			return 0, nil, ErrMessageTooLarge
+		}
+
+		message = append(message, payload...)
+
+		if fin {
+			return messageType, message, nil
+		}
+	}
+}
+
+// WriteMessage writes a data message as a single frame.
+func (c *Conn) WriteMessage(messageType MessageType, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return werrors.Wrap(ErrProtocol, "unsupported message type %d", messageType)
+	}
+
+	return c.writeFrame(wsproto.Opcode(messageType), data)
+}
+
+// Ping writes a ping frame.
+func (c *Conn) Ping(data []byte) error {
+	if len(data) > wsproto.MaxControlPayloadLen {
+		return ErrMessageTooLarge
+	}
+
+	return c.writeFrame(wsproto.OpPing, data)
+}
+
+// SetReadDeadline sets the deadline for reading messages.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	if err := c.conn.SetReadDeadline(t); err != nil {
+		return werrors.Wrap(err, "failed to set read deadline")
+	}
+
+	return nil
+}
+
+// Close sends a close frame and closes the underlying connection.
+func (c *Conn) Close() error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(closeWriteTimeout))
+
+	if err := c.writeFrame(wsproto.OpClose, wsproto.ClosePayload(CloseNormalClosure, "")); errors.Is(err, ErrClosed) {
+		return nil
+	}
+
+	return c.closeConn()
+}
+
+func (c *Conn) handleClose(payload []byte) error {
+	closeErr := CloseError{}
+	closeErr.Code, closeErr.Reason = wsproto.ParseClosePayload(payload)
+
+	// echo the status code only, as the specification requires
+	_ = c.writeFrame(wsproto.OpClose, payload[:min(len(payload), 2)]) //nolint:mnd
+	_ = c.closeConn()
+
+	return closeErr
+}
+
+func (c *Conn) closeConn() error {
+	c.closeOnce.Do(func() {
+		c.writeMu.Lock()
+		c.closed = true
+		c.writeMu.Unlock()
+
+		if err := c.conn.Close(); err != nil {
+			c.closeErr = werrors.Wrap(err, "failed to close the connection")
+		}
+	})
+
+	return c.closeErr
+}
+
+func (c *Conn) writeFrame(op wsproto.Opcode, payload []byte) error {
+	// frames of the client are always masked
+	frame, err := wsproto.EncodeFrame(op, payload, true)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	if _, err := c.conn.Write(frame); err != nil {
+		return werrors.Wrap(err, "failed to write a frame")
+	}
+
+	return nil
+}
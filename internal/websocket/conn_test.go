@@ -0,0 +1,100 @@
+package websocket_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gromson/capitalcom/internal/websocket"
+	"github.com/gromson/capitalcom/internal/wstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDial_EchoesMessages(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(echo))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	largeMessage := strings.Repeat("a", 70000)
+
+	for _, message := range []string{"hello", strings.Repeat("b", 300), largeMessage} {
+		// Act
+		require.NoError(t, underTest.Ping([]byte("ping")))
+		require.NoError(t, underTest.WriteMessage(websocket.TextMessage, []byte(message)))
+
+		messageType, got, err := underTest.ReadMessage()
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, websocket.TextMessage, messageType)
+		require.Equal(t, message, string(got))
+	}
+}
+
+func TestConn_ReadMessageReturnsCloseError(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		_ = conn.Close()
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+
+	var closeErr websocket.CloseError
+
+	// Act
+	_, _, err = underTest.ReadMessage()
+
+	// Assert
+	require.ErrorAs(t, err, &closeErr)
+	require.Equal(t, websocket.CloseNormalClosure, closeErr.Code)
+	require.NoError(t, underTest.Close())
+}
+
+func TestDial_FailsOnNonWebSocketEndpoint(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	// Act
+	_, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+
+	// Assert
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+}
@@ -0,0 +1,265 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gromson/capitalcom/internal/wsproto"
+	werrors "github.com/gromson/capitalcom/pkg/errors"
+)
+
+const keyLen = 16
+
+// Dialer opens client connections, the zero value dials directly with the default TLS configuration.
+// The fields mirror the ones of http.Transport, so the connections can follow the same network setup
+// as the HTTP requests.
+type Dialer struct {
+	// Proxy returns the proxy URL for a request to the http:// or https:// equivalent of the URL,
+	// see http.Transport.Proxy. Only http:// proxies are supported, the connection is tunneled
+	// with the CONNECT method. A nil function or URL means no proxy.
+	Proxy func(*http.Request) (*url.URL, error)
+	// TLSClientConfig is the configuration of the wss:// connections, ServerName defaults to the URL host.
+	// NextProtos is always replaced with http/1.1.
+	TLSClientConfig *tls.Config
+	// NetDialContext dials the TCP connections to the host or the proxy, net.Dialer is used if nil.
+	NetDialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Dial opens a client connection to the ws:// or wss:// URL with the zero Dialer, see Dialer.Dial.
+func Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, error) {
+	return (&Dialer{}).Dial(ctx, rawURL, header)
+}
+
+// Dial opens a client connection to the ws:// or wss:// URL.
+// Deadline of the context applies to the opening handshake only.
+func (d *Dialer) Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, werrors.Wrap(err, "failed to parse the URL")
+	}
+
+	conn, err := d.dialConn(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	wsConn, err := handshake(ctx, conn, u, header)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return wsConn, nil
+}
+
+// dialConn opens the TCP connection to the host, through the proxy if any, and wraps it in TLS for wss:// URLs.
+func (d *Dialer) dialConn(ctx context.Context, u *url.URL) (net.Conn, error) {
+	var httpScheme, defaultPort string
+
+	switch u.Scheme {
+	case "ws":
+		httpScheme, defaultPort = "http", "80"
+	case "wss":
+		httpScheme, defaultPort = "https", "443"
+	default:
+		return nil, werrors.Wrap(ErrBadHandshake, "unsupported URL scheme %q", u.Scheme)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+
+	proxyURL, err := d.proxyURL(u, httpScheme)
+	if err != nil {
+		return nil, err
+	}
+
+	dialAddr := addr
+
+	if proxyURL != nil {
+		dialAddr = proxyURL.Host
+		if proxyURL.Port() == "" {
+			dialAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		}
+	}
+
+	conn, err := d.netDial(ctx, dialAddr)
+	if err != nil {
+		return nil, werrors.Wrap(err, "failed to dial %s", dialAddr)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if proxyURL != nil {
+		if err := connectThroughProxy(ctx, conn, addr, proxyURL); err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+	}
+
+	if httpScheme == "http" {
+		return conn, nil
+	}
+
+	tlsConn := tls.Client(conn, d.tlsConfig(u))
+
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+
+		return nil, werrors.Wrap(err, "failed to perform a TLS handshake with %s", addr)
+	}
+
+	return tlsConn, nil
+}
+
+func (d *Dialer) proxyURL(u *url.URL, httpScheme string) (*url.URL, error) {
+	if d.Proxy == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	httpURL := *u
+	httpURL.Scheme = httpScheme
+
+	proxyURL, err := d.Proxy(&http.Request{Method: http.MethodGet, URL: &httpURL, Header: make(http.Header)})
+	if err != nil {
+		return nil, werrors.Wrap(err, "failed to resolve the proxy")
+	}
+
+	if proxyURL != nil && proxyURL.Scheme != "http" {
+		return nil, werrors.Wrap(ErrBadHandshake, "unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+
+	return proxyURL, nil
+}
+
+func (d *Dialer) netDial(ctx context.Context, addr string) (net.Conn, error) {
+	if d.NetDialContext != nil {
+		return d.NetDialContext(ctx, "tcp", addr)
+	}
+
+	dialer := &net.Dialer{}
+
+	return dialer.DialContext(ctx, "tcp", addr) //nolint:wrapcheck
+}
+
+func (d *Dialer) tlsConfig(u *url.URL) *tls.Config {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if d.TLSClientConfig != nil {
+		cfg = d.TLSClientConfig.Clone()
+	}
+
+	if cfg.ServerName == "" {
+		cfg.ServerName = u.Hostname()
+	}
+
+	// the upgrade is an HTTP/1.1 request, while a config of an http.Transport may offer h2 as well
+	cfg.NextProtos = []string{"http/1.1"}
+
+	return cfg
+}
+
+// connectThroughProxy opens a tunnel to the address with the CONNECT method.
+func connectThroughProxy(ctx context.Context, conn net.Conn, addr string, proxyURL *url.URL) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodConnect, "http://"+addr, nil)
+	if err != nil {
+		return werrors.Wrap(err, "failed to create a proxy request")
+	}
+
+	req.Host = addr
+
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return werrors.Wrap(err, "failed to send a proxy request")
+	}
+
+	// the proxy sends nothing after the response until the tunnel is used, so no data is lost in the buffer
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return werrors.Wrap(err, "failed to read a proxy response")
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return werrors.Wrap(ErrBadHandshake, "proxy responded with status %q", res.Status)
+	}
+
+	return nil
+}
+
+func handshake(ctx context.Context, conn net.Conn, u *url.URL, header http.Header) (*Conn, error) {
+	key, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, werrors.Wrap(err, "failed to create a handshake request")
+	}
+
+	for k, values := range header {
+		for _, value := range values {
+			req.Header.Add(k, value)
+		}
+	}
+
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(conn); err != nil {
+		return nil, werrors.Wrap(err, "failed to send a handshake request")
+	}
+
+	reader := bufio.NewReader(conn)
+
+	res, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, werrors.Wrap(err, "failed to read a handshake response")
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode != http.StatusSwitchingProtocols ||
+		!wsproto.HeaderContains(res.Header, "Upgrade", "websocket") ||
+		!wsproto.HeaderContains(res.Header, "Connection", "upgrade") {
+		return nil, werrors.Wrap(ErrBadHandshake, "unexpected handshake response status %q", res.Status)
+	}
+
+	if res.Header.Get("Sec-WebSocket-Accept") != wsproto.AcceptKey(key) {
+		return nil, werrors.Wrap(ErrBadHandshake, "invalid Sec-WebSocket-Accept")
+	}
+
+	return newConn(conn, reader), nil
+}
+
+func generateKey() (string, error) {
+	key := make([]byte, keyLen)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", werrors.Wrap(err, "failed to generate a handshake key")
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
@@ -0,0 +1,180 @@
+package websocket_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gromson/capitalcom/internal/websocket"
+	"github.com/gromson/capitalcom/internal/wstest"
+	"github.com/stretchr/testify/require"
+)
+
+func echo(w http.ResponseWriter, r *http.Request) {
+	conn, err := wstest.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if err := conn.WriteMessage(messageType, message); err != nil {
+			return
+		}
+	}
+}
+
+// newConnectProxy tunnels CONNECT requests to the requested hosts and counts them.
+func newConnectProxy(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	tunnels := &atomic.Int32{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		defer target.Close()
+
+		client, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+
+		defer client.Close()
+
+		tunnels.Add(1)
+
+		_, _ = client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+
+		go func() {
+			_, _ = io.Copy(target, client)
+		}()
+
+		_, _ = io.Copy(client, target)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, tunnels
+}
+
+func requireEcho(t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+
+	messageType, got, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.TextMessage, messageType)
+	require.Equal(t, "hello", string(got))
+}
+
+func TestDialer_DialUsesTLSClientConfig(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv := httptest.NewTLSServer(http.HandlerFunc(echo))
+	t.Cleanup(srv.Close)
+
+	transport, ok := srv.Client().Transport.(*http.Transport)
+	require.True(t, ok)
+
+	underTest := &websocket.Dialer{TLSClientConfig: transport.TLSClientConfig}
+
+	// Act
+	conn, err := underTest.Dial(context.Background(), "wss"+strings.TrimPrefix(srv.URL, "https"), nil)
+	_, untrustedErr := websocket.Dial(context.Background(), "wss"+strings.TrimPrefix(srv.URL, "https"), nil)
+
+	// Assert
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	requireEcho(t, conn)
+	require.Error(t, untrustedErr)
+}
+
+func TestDialer_DialNegotiatesHTTP1WithHTTP2Server(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(echo))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	transport, ok := srv.Client().Transport.(*http.Transport)
+	require.True(t, ok)
+
+	// an HTTP/2 request makes the transport add h2 to the protocols of its config
+	res, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	require.Contains(t, transport.TLSClientConfig.NextProtos, "h2")
+
+	underTest := &websocket.Dialer{TLSClientConfig: transport.TLSClientConfig}
+
+	// Act
+	conn, err := underTest.Dial(context.Background(), "wss"+strings.TrimPrefix(srv.URL, "https"), nil)
+
+	// Assert
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	requireEcho(t, conn)
+}
+
+func TestDialer_DialTunnelsThroughProxy(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(echo))
+	t.Cleanup(srv.Close)
+
+	proxy, tunnels := newConnectProxy(t)
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	underTest := &websocket.Dialer{Proxy: http.ProxyURL(proxyURL)}
+
+	// Act
+	conn, err := underTest.Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+
+	// Assert
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	requireEcho(t, conn)
+	require.Equal(t, int32(1), tunnels.Load())
+}
@@ -0,0 +1,216 @@
+// Package wsproto implements the WebSocket (RFC 6455) framing and handshake primitives shared by the client
+// in internal/websocket and the test server in internal/wstest.
+package wsproto
+
+import (
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	werrors "github.com/gromson/capitalcom/pkg/errors"
+)
+
+// AcceptGUID is a magic value used to compute Sec-WebSocket-Accept, RFC 6455 section 1.3.
+const AcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcode is the type of a frame.
+type Opcode byte
+
+const (
+	OpContinuation Opcode = 0x0
+	OpText         Opcode = 0x1
+	OpBinary       Opcode = 0x2
+	OpClose        Opcode = 0x8
+	OpPing         Opcode = 0x9
+	OpPong         Opcode = 0xA
+)
+
+const (
+	// CloseNoStatusReceived is reported when a close frame has no status code.
+	CloseNoStatusReceived = 1005
+
+	// MaxControlPayloadLen is the maximum payload length of the control frames.
+	MaxControlPayloadLen = 125
+
+	finBit  = 0x80
+	maskBit = 0x80
+
+	opcodeMask     = 0x0F
+	payloadLenMask = 0x7F
+	reservedMask   = 0x70
+
+	payloadLen16 = 126
+	payloadLen64 = 127
+
+	maskKeyLen        = 4
+	maxFrameHeaderLen = 14
+)
+
+var (
+	ErrProtocol        = errors.New("websocket: protocol error")
+	ErrMessageTooLarge = errors.New("websocket: message is too large")
+)
+
+// ReadFrame reads a frame and unmasks its payload, a payload longer than maxPayloadLen results in ErrMessageTooLarge.
+func ReadFrame(r io.Reader, maxPayloadLen uint64) (bool, Opcode, []byte, error) {
+	header := make([]byte, 2) //nolint:mnd
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return false, 0, nil, werrors.Wrap(err, "failed to read a frame header")
+	}
+
+	if header[0]&reservedMask != 0 {
+		return false, 0, nil, werrors.Wrap(ErrProtocol, "reserved bits are set")
+	}
+
+	fin := header[0]&finBit != 0
+	op := Opcode(header[0] & opcodeMask)
+	masked := header[1]&maskBit != 0
+
+	length, err := readPayloadLength(r, header[1]&payloadLenMask)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if op >= OpClose && (length > MaxControlPayloadLen || !fin) {
+		return false, 0, nil, werrors.Wrap(ErrProtocol, "invalid control frame")
+	}
+
+	if length > maxPayloadLen {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	var maskKey [maskKeyLen]byte
+
+	if masked {
+		if _, err := io.ReadFull(r, maskKey[:]); err != nil {
+			return false, 0, nil, werrors.Wrap(err, "failed to read a frame mask key")
+		}
+	}
+
+	payload := make([]byte, length)
+
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, werrors.Wrap(err, "failed to read a frame payload")
+	}
+
+	if masked {
+		maskBytes(maskKey, payload)
+	}
+
+	return fin, op, payload, nil
+}
+
+func readPayloadLength(r io.Reader, length byte) (uint64, error) {
+	switch length {
+	case payloadLen16:
+		ext := make([]byte, 2) //nolint:mnd
+
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, werrors.Wrap(err, "failed to read a frame length")
+		}
+
+		return uint64(binary.BigEndian.Uint16(ext)), nil
+	case payloadLen64:
+		ext := make([]byte, 8) //nolint:mnd
+
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, werrors.Wrap(err, "failed to read a frame length")
+		}
+
+		return binary.BigEndian.Uint64(ext), nil
+	default:
+		return uint64(length), nil
+	}
+}
+
+// EncodeFrame encodes a final frame, the payload is masked with a random key if masked is set,
+// which is required for the frames sent by a client.
+func EncodeFrame(op Opcode, payload []byte, masked bool) ([]byte, error) {
+	frame := make([]byte, 0, maxFrameHeaderLen+len(payload))
+	frame = append(frame, finBit|byte(op))
+
+	var maskFlag byte
+
+	if masked {
+		maskFlag = maskBit
+	}
+
+	length := len(payload)
+
+	switch {
+	case length < payloadLen16:
+		frame = append(frame, maskFlag|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, maskFlag|payloadLen16)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, maskFlag|payloadLen64)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	if !masked {
+		return append(frame, payload...), nil
+	}
+
+	var maskKey [maskKeyLen]byte
+
+	if _, err := rand.Read(maskKey[:]); err != nil {
+		return nil, werrors.Wrap(err, "failed to generate a mask key")
+	}
+
+	data := append([]byte(nil), payload...)
+	maskBytes(maskKey, data)
+
+	frame = append(frame, maskKey[:]...)
+
+	return append(frame, data...), nil
+}
+
+func maskBytes(key [maskKeyLen]byte, data []byte) {
+	for i := range data {
+		data[i] ^= key[i%maskKeyLen]
+	}
+}
+
+// ClosePayload encodes the payload of a close frame.
+func ClosePayload(code int, reason string) []byte {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code)) //nolint:gosec
+
+	return append(payload, reason...)
+}
+
+// ParseClosePayload decodes the payload of a close frame, the code is CloseNoStatusReceived if the payload is empty.
+func ParseClosePayload(payload []byte) (int, string) {
+	if len(payload) < 2 { //nolint:mnd
+		return CloseNoStatusReceived, ""
+	}
+
+	return int(binary.BigEndian.Uint16(payload)), string(payload[2:])
+}
+
+// AcceptKey returns the Sec-WebSocket-Accept value for the Sec-WebSocket-Key of a handshake request.
+func AcceptKey(key string) string {
+	h := sha1.New() //nolint:gosec
+	h.Write([]byte(key + AcceptGUID))
+
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// HeaderContains reports whether the comma separated values of the header contain the token, ignoring case.
+func HeaderContains(header http.Header, name, token string) bool {
+	for _, v := range header.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+// Package wstest implements the server side of a WebSocket connection for testing the streaming clients.
+// It supports unfragmented text and binary messages, ping and close frames only.
+package wstest
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/gromson/capitalcom/internal/websocket"
+	"github.com/gromson/capitalcom/internal/wsproto"
+)
+
+var ErrBadHandshake = errors.New("wstest: bad handshake")
+
+// Conn is a server WebSocket connection, writes are safe to be used concurrently.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+}
+
+// Upgrade upgrades a server HTTP connection to the WebSocket protocol.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if r.Method != http.MethodGet || !wsproto.HeaderContains(r.Header, "Upgrade", "websocket") || key == "" {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+
+		return nil, ErrBadHandshake
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return nil, ErrBadHandshake
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsproto.AcceptKey(key) + "\r\n\r\n"
+
+	if _, err := conn.Write([]byte(response)); err != nil {
+		_ = conn.Close()
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &Conn{conn: conn, reader: rw.Reader}, nil
+}
+
+// ReadMessage reads the next data message, pings are answered automatically.
+// A websocket.CloseError is returned when the client has closed the connection.
+func (c *Conn) ReadMessage() (websocket.MessageType, []byte, error) {
+	for {
+		_, op, payload, err := wsproto.ReadFrame(c.reader, websocket.MaxMessageSize)
+		if err != nil {
+			return 0, nil, err //nolint:wrapcheck
+		}
+
+		switch op {
+		case wsproto.OpPing:
+			if err := c.writeFrame(wsproto.OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+		case wsproto.OpPong:
+		case wsproto.OpClose:
+			closeErr := websocket.CloseError{}
+			closeErr.Code, closeErr.Reason = wsproto.ParseClosePayload(payload)
+
+			_ = c.writeFrame(wsproto.OpClose, payload[:min(len(payload), 2)]) //nolint:mnd
+			_ = c.conn.Close()
+
+			return 0, nil, closeErr
+		default:
+			return websocket.MessageType(op), payload, nil
+		}
+	}
+}
+
+// WriteMessage writes a data message as a single frame.
+func (c *Conn) WriteMessage(messageType websocket.MessageType, data []byte) error {
+	return c.writeFrame(wsproto.Opcode(messageType), data)
+}
+
+// Close sends a normal closure frame and closes the underlying connection.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(wsproto.OpClose, wsproto.ClosePayload(websocket.CloseNormalClosure, ""))
+
+	return c.conn.Close() //nolint:wrapcheck
+}
+
+// writeFrame writes an unmasked frame as the server side requires.
+func (c *Conn) writeFrame(op wsproto.Opcode, payload []byte) error {
+	frame, err := wsproto.EncodeFrame(op, payload, false)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err = c.conn.Write(frame)
+
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,129 @@
+package capitalcom
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultKeepAliveInterval is a ping interval that keeps a session alive,
+	// the API closes a session after 10 minutes of inactivity.
+	DefaultKeepAliveInterval = 5 * time.Minute
+
+	defaultKeepAliveMaxFailures = 2
+)
+
+// KeepAlive is a handle of a running session keep-alive loop.
+type KeepAlive struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Stop stops the keep-alive loop and waits until it exits.
+func (k *KeepAlive) Stop() {
+	k.cancel()
+	<-k.done
+}
+
+// Done returns a channel that is closed when the keep-alive loop exits.
+func (k *KeepAlive) Done() <-chan struct{} {
+	return k.done
+}
+
+type keepAliveConfig struct {
+	maxFailures  int
+	errorHandler func(error)
+}
+
+// KeepAliveOption is a functional for setting the config option for the keep-alive loop.
+type KeepAliveOption func(*keepAliveConfig)
+
+// WithKeepAliveErrorHandler sets a handler that is called for every failed ping or session renewal,
+// a nil handler is ignored.
+func WithKeepAliveErrorHandler(handler func(error)) KeepAliveOption {
+	return func(c *keepAliveConfig) {
+		if handler != nil {
+			c.errorHandler = handler
+		}
+	}
+}
+
+// WithKeepAliveMaxFailures sets the number of consecutive failed pings after which a new session is created.
+func WithKeepAliveMaxFailures(maxFailures int) KeepAliveOption {
+	return func(c *keepAliveConfig) {
+		c.maxFailures = maxFailures
+	}
+}
+
+// StartKeepAlive starts pinging the API with the given interval to keep the session tokens valid.
+// If pings keep failing the session is created again. The loop runs until the context is done
+// or the returned handle is stopped.
+func (c *Client) StartKeepAlive(ctx context.Context, interval time.Duration, opts ...KeepAliveOption) *KeepAlive {
+	if interval <= 0 {
+		interval = DefaultKeepAliveInterval
+	}
+
+	cfg := &keepAliveConfig{
+		maxFailures:  defaultKeepAliveMaxFailures,
+		errorHandler: func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	k := &KeepAlive{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go c.keepAlive(ctx, interval, cfg, k.done)
+
+	return k
+}
+
+func (c *Client) keepAlive(ctx context.Context, interval time.Duration, cfg *keepAliveConfig, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := c.Ping(ctx)
+		if err == nil {
+			failures = 0
+
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		failures++
+
+		cfg.errorHandler(err)
+
+		if failures < cfg.maxFailures {
+			continue
+		}
+
+		if err := c.reauthenticate(ctx, ""); err != nil {
+			cfg.errorHandler(err)
+
+			continue
+		}
+
+		failures = 0
+	}
+}
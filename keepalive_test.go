@@ -0,0 +1,163 @@
+package capitalcom_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_StartKeepAlivePingsPeriodically(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var pingsReceived atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			handleSessionCreation(w, r)
+		case capitalcom.APIPathV1 + "/ping":
+			if !isAuthorized(w, r) {
+				return
+			}
+
+			pingsReceived.Add(1)
+			_, _ = w.Write([]byte(`{ "status": "OK" }`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	// Act
+	keepAlive := underTest.StartKeepAlive(ctx, 5*time.Millisecond)
+
+	// Assert
+	require.Eventually(t, func() bool {
+		return pingsReceived.Load() >= 3
+	}, time.Second, time.Millisecond)
+
+	keepAlive.Stop()
+
+	select {
+	case <-keepAlive.Done():
+	default:
+		t.Fatal("keep-alive loop is still running after Stop")
+	}
+}
+
+func TestClient_StartKeepAliveRenewsSessionAfterFailedPings(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var sessionsCreated, errorsReported atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			sessionsCreated.Add(1)
+			handleSessionCreation(w, r)
+		case capitalcom.APIPathV1 + "/ping":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	// Act
+	keepAlive := underTest.StartKeepAlive(ctx,
+		5*time.Millisecond,
+		capitalcom.WithKeepAliveMaxFailures(2),
+		capitalcom.WithKeepAliveErrorHandler(func(error) {
+			errorsReported.Add(1)
+		}))
+
+	t.Cleanup(keepAlive.Stop)
+
+	// Assert
+	require.Eventually(t, func() bool {
+		return sessionsCreated.Load() >= 2 && errorsReported.Load() >= 2
+	}, time.Second, time.Millisecond)
+}
+
+func TestClient_StartKeepAliveIgnoresNilErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var sessionsCreated atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			sessionsCreated.Add(1)
+			handleSessionCreation(w, r)
+		case capitalcom.APIPathV1 + "/ping":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	// Act
+	keepAlive := underTest.StartKeepAlive(ctx,
+		5*time.Millisecond,
+		capitalcom.WithKeepAliveMaxFailures(1),
+		capitalcom.WithKeepAliveErrorHandler(nil))
+
+	t.Cleanup(keepAlive.Stop)
+
+	// Assert
+	require.Eventually(t, func() bool {
+		return sessionsCreated.Load() >= 2
+	}, time.Second, time.Millisecond)
+}
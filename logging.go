@@ -0,0 +1,130 @@
+package capitalcom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WithRequestLogLevels sets the levels of the structured records logged for every request,
+// successLevel for succeeded requests and failureLevel for failed ones. Both are slog.LevelDebug by default.
+func WithRequestLogLevels(successLevel, failureLevel slog.Level) ClientOption {
+	return func(c *Client) {
+		c.requestLogSuccessLevel = successLevel
+		c.requestLogFailureLevel = failureLevel
+	}
+}
+
+// requestResult is an outcome of a request reported by the structured request log.
+type requestResult struct {
+	method       string
+	resourcePath string
+	reqBody      []byte
+	latency      time.Duration
+	res          *http.Response
+	resPayload   any
+	err          error
+}
+
+func (c *Client) logRequestResult(ctx context.Context, result requestResult) {
+	level := c.requestLogSuccessLevel
+	if result.err != nil {
+		level = c.requestLogFailureLevel
+	}
+
+	if c.logger == nil || !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	path, _, _ := strings.Cut(result.resourcePath, "?")
+
+	attrs := []slog.Attr{
+		slog.String("method", result.method),
+		slog.String("path", path),
+		slog.Duration("latency", result.latency),
+	}
+
+	var apiErr APIError
+
+	switch {
+	case errors.As(result.err, &apiErr):
+		attrs = append(attrs,
+			slog.Int("statusCode", apiErr.StatusCode()),
+			slog.String("errorCode", apiErr.ErrorCode()))
+	case result.res != nil:
+		attrs = append(attrs, slog.Int("statusCode", result.res.StatusCode))
+	}
+
+	if result.err != nil {
+		attrs = append(attrs, slog.Any("error", result.err))
+	}
+
+	attrs = append(attrs, dealAttrs(path, result.reqBody, result.resPayload)...)
+
+	msg := "request succeeded"
+	if result.err != nil {
+		msg = "request failed"
+	}
+
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// dealAttrs returns the epic, the deal ID and the deal reference of the request if they are known.
+func dealAttrs(path string, reqBody []byte, resPayload any) []slog.Attr {
+	var epic, dealID, dealReference string
+
+	resource, id, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	// the ID is escaped in the path, it is logged the same way as the values taken from the bodies
+	if unescaped, err := url.PathUnescape(id); err == nil {
+		id = unescaped
+	}
+
+	switch resource {
+	case "markets", "prices":
+		epic = id
+	case "positions", "workingorders":
+		dealID = id
+	case "confirms":
+		dealReference = id
+	}
+
+	if len(reqBody) > 0 {
+		payload := struct {
+			Epic string `json:"epic"`
+		}{}
+
+		if err := json.Unmarshal(reqBody, &payload); err == nil && payload.Epic != "" {
+			epic = payload.Epic
+		}
+	}
+
+	switch payload := resPayload.(type) {
+	case *dealReferenceResponsePayload:
+		dealReference = payload.DealReference
+	case *Deal:
+		epic = payload.Epic
+		dealID = payload.DealID
+	}
+
+	var attrs []slog.Attr
+
+	if epic != "" {
+		attrs = append(attrs, slog.String("epic", epic))
+	}
+
+	if dealID != "" {
+		attrs = append(attrs, slog.String("dealId", dealID))
+	}
+
+	if dealReference != "" {
+		attrs = append(attrs, slog.String("dealReference", dealReference))
+	}
+
+	return attrs
+}
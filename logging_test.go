@@ -0,0 +1,120 @@
+package capitalcom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoggingClient(t *testing.T, handler http.HandlerFunc) (*capitalcom.Client, *bytes.Buffer) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithLogger(logger),
+		capitalcom.WithRequestLogLevels(slog.LevelInfo, slog.LevelWarn))
+
+	return client, logs
+}
+
+func decodeLogRecord(t *testing.T, logs *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	record := map[string]any{}
+
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+
+	return record
+}
+
+func TestClient_LogsSucceededRequest(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, logs := newLoggingClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"dealReference":"o_12345"}`))
+	})
+
+	// Act
+	_, err := underTest.Positions().Open(context.Background(), capitalcom.OpenPositionRequest{
+		Direction: capitalcom.PositionDirectionBuy,
+		Epic:      "BTCUSD",
+		Size:      1,
+	})
+
+	// Assert
+	require.NoError(t, err)
+
+	record := decodeLogRecord(t, logs)
+
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "request succeeded", record["msg"])
+	require.Equal(t, "POST", record["method"])
+	require.Equal(t, "/positions", record["path"])
+	require.InDelta(t, http.StatusOK, record["statusCode"], 0)
+	require.Equal(t, "BTCUSD", record["epic"])
+	require.Equal(t, "o_12345", record["dealReference"])
+	require.Contains(t, record, "latency")
+}
+
+func TestClient_LogsFailedRequest(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, logs := newLoggingClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorCode":"error.not-found.dealId"}`))
+	})
+
+	// Act
+	err := underTest.Positions().Close(context.Background(), "006011e7-0055-311e-0000-000080507631")
+
+	// Assert
+	require.Error(t, err)
+
+	record := decodeLogRecord(t, logs)
+
+	require.Equal(t, "WARN", record["level"])
+	require.Equal(t, "request failed", record["msg"])
+	require.Equal(t, "DELETE", record["method"])
+	require.Equal(t, "/positions/006011e7-0055-311e-0000-000080507631", record["path"])
+	require.InDelta(t, http.StatusNotFound, record["statusCode"], 0)
+	require.Equal(t, "error.not-found.dealId", record["errorCode"])
+	require.Equal(t, "006011e7-0055-311e-0000-000080507631", record["dealId"])
+}
+
+func TestClient_LogsUnescapedEpic(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, logs := newLoggingClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	// Act
+	_, err := underTest.Markets().Detail(context.Background(), "US 500/CASH")
+
+	// Assert
+	require.NoError(t, err)
+
+	record := decodeLogRecord(t, logs)
+
+	require.Equal(t, "/markets/US%20500%2FCASH", record["path"])
+	require.Equal(t, "US 500/CASH", record["epic"])
+}
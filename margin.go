@@ -0,0 +1,130 @@
+package capitalcom
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MarginCalculator calculates margin requirements and position sizes for a market. The amounts are
+// in the currency of the instrument, the balance has to be converted to it if the account currency differs.
+type MarginCalculator struct {
+	market   MarketDetails
+	leverage int
+}
+
+// NewMarginCalculator creates a calculator for the market and the account preferences,
+// see Markets.Detail and Account.Preferences.
+func NewMarginCalculator(market MarketDetails, preferences Preferences) *MarginCalculator {
+	leverage, _ := preferences.Leverages.ForInstrumentType(market.Instrument.Type)
+
+	return &MarginCalculator{
+		market:   market,
+		leverage: leverage.Current,
+	}
+}
+
+// RequiredMargin returns the margin required to open the position at the current market price.
+func (c *MarginCalculator) RequiredMargin(req OpenPositionRequest) (float64, error) {
+	perUnit, err := c.marginPerUnit(req.Direction)
+	if err != nil {
+		return 0, err
+	}
+
+	return req.Size * perUnit, nil
+}
+
+// MaxSize returns the largest size of a position in the direction the available balance is enough for.
+// The size is rounded down to the size increment and limited by the maximum deal size, it is 0 if the balance
+// isn't enough for the minimum deal size.
+func (c *MarginCalculator) MaxSize(direction PositionDirection, balance Balance) (float64, error) {
+	perUnit, err := c.marginPerUnit(direction)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.roundSize(balance.Available / perUnit), nil
+}
+
+// RiskSize returns the size of a position in the direction risking the percent of the equity if the stop loss
+// at the distance is hit. The equity is the balance, which already includes the profit and loss of the open positions.
+// The size is limited by MaxSize and rounded the same way.
+func (c *MarginCalculator) RiskSize(
+	direction PositionDirection,
+	balance Balance,
+	stopDistance float64,
+	riskPercent float64,
+) (float64, error) {
+	if stopDistance <= 0 || riskPercent <= 0 {
+		return 0, ErrInvalidRisk
+	}
+
+	maxSize, err := c.MaxSize(direction, balance)
+	if err != nil {
+		return 0, err
+	}
+
+	size := balance.Balance * riskPercent / 100 / stopDistance //nolint:mnd
+
+	return min(c.roundSize(size), maxSize), nil
+}
+
+// marginPerUnit returns the margin required for a unit of size. If both the margin factor of the instrument
+// and the leverage of the account are known, the larger requirement is used.
+func (c *MarginCalculator) marginPerUnit(direction PositionDirection) (float64, error) {
+	price := c.market.Snapshot.Offer
+	if direction == PositionDirectionSell {
+		price = c.market.Snapshot.Bid
+	}
+
+	instrument := c.market.Instrument
+
+	var perUnit float64
+
+	switch {
+	case instrument.MarginFactor <= 0:
+	case instrument.MarginFactorUnit == RuleUnitPoints:
+		perUnit = instrument.MarginFactor
+	default:
+		perUnit = price * instrument.MarginFactor / 100 //nolint:mnd
+	}
+
+	if c.leverage > 0 {
+		perUnit = max(perUnit, price/float64(c.leverage))
+	}
+
+	if perUnit <= 0 {
+		return 0, ErrUnknownMargin
+	}
+
+	return perUnit, nil
+}
+
+// roundSize rounds the size down to the size increment and limits it by the dealing rules.
+func (c *MarginCalculator) roundSize(size float64) float64 {
+	rules := c.market.DealingRules
+
+	if increment := rules.MinSizeIncrement.Value; increment > 0 {
+		size = math.Floor(size/increment+sizeIncrementTolerance) * increment
+
+		// remove the float rounding errors, e.g. 0.30000000000000004
+		precision := math.Pow10(decimalPlaces(increment))
+		size = math.Round(size*precision) / precision
+	}
+
+	if maxSize := rules.MaxDealSize.Value; maxSize > 0 {
+		size = min(size, maxSize)
+	}
+
+	if size < rules.MinDealSize.Value || size <= 0 {
+		return 0
+	}
+
+	return size
+}
+
+func decimalPlaces(value float64) int {
+	_, fraction, _ := strings.Cut(strconv.FormatFloat(value, 'f', -1, 64), ".")
+
+	return len(fraction)
+}
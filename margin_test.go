@@ -0,0 +1,138 @@
+package capitalcom_test
+
+import (
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func marginMarket() capitalcom.MarketDetails {
+	return capitalcom.MarketDetails{
+		Instrument: capitalcom.Instrument{
+			Type:             capitalcom.InstrumentTypeCryptocurrencies,
+			MarginFactor:     20,
+			MarginFactorUnit: capitalcom.RuleUnitPercentage,
+		},
+		DealingRules: capitalcom.DealingRules{
+			MinDealSize:      capitalcom.Rule{Value: 0.1},
+			MaxDealSize:      capitalcom.Rule{Value: 1000},
+			MinSizeIncrement: capitalcom.Rule{Value: 0.01},
+		},
+		Snapshot: capitalcom.Snapshot{Bid: 100, Offer: 101},
+	}
+}
+
+func marginPreferences() capitalcom.Preferences {
+	return capitalcom.Preferences{
+		Leverages: capitalcom.Leverages{Cryptocurrencies: capitalcom.Leverage{Current: 2}},
+	}
+}
+
+func TestMarginCalculator_RequiredMargin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		market      func(market *capitalcom.MarketDetails)
+		preferences capitalcom.Preferences
+		direction   capitalcom.PositionDirection
+		expected    float64
+	}{
+		{
+			name:        "leverage requires more than the margin factor",
+			preferences: marginPreferences(),
+			direction:   capitalcom.PositionDirectionBuy,
+			expected:    101,
+		},
+		{
+			name:      "margin factor in percents",
+			direction: capitalcom.PositionDirectionSell,
+			expected:  40,
+		},
+		{
+			name: "margin factor in points",
+			market: func(market *capitalcom.MarketDetails) {
+				market.Instrument.MarginFactor = 10
+				market.Instrument.MarginFactorUnit = capitalcom.RuleUnitPoints
+			},
+			direction: capitalcom.PositionDirectionBuy,
+			expected:  20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			market := marginMarket()
+			if tt.market != nil {
+				tt.market(&market)
+			}
+
+			underTest := capitalcom.NewMarginCalculator(market, tt.preferences)
+
+			// Act
+			actual, err := underTest.RequiredMargin(capitalcom.OpenPositionRequest{Direction: tt.direction, Size: 2})
+
+			// Assert
+			require.NoError(t, err)
+			require.InDelta(t, tt.expected, actual, 1e-9)
+		})
+	}
+}
+
+func TestMarginCalculator_MaxSize(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewMarginCalculator(marginMarket(), marginPreferences())
+
+	// Act
+	actual, err := underTest.MaxSize(capitalcom.PositionDirectionBuy, capitalcom.Balance{Available: 1000})
+	tooSmall, tooSmallErr := underTest.MaxSize(capitalcom.PositionDirectionBuy, capitalcom.Balance{Available: 3})
+
+	// Assert
+	require.NoError(t, err)
+	require.InDelta(t, 19.8, actual, 1e-12)
+	require.NoError(t, tooSmallErr)
+	require.Zero(t, tooSmall)
+}
+
+func TestMarginCalculator_RiskSize(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewMarginCalculator(marginMarket(), marginPreferences())
+	// the balance already includes the profit and loss: the deposit of 1200 minus the loss of 200
+	balance := capitalcom.Balance{Balance: 1000, Deposit: 1200, ProfitLoss: -200, Available: 1000}
+
+	// Act
+	actual, err := underTest.RiskSize(capitalcom.PositionDirectionBuy, balance, 3, 1)
+	limited, limitedErr := underTest.RiskSize(capitalcom.PositionDirectionBuy, balance, 3, 50)
+	_, invalidErr := underTest.RiskSize(capitalcom.PositionDirectionBuy, balance, 0, 1)
+
+	// Assert
+	require.NoError(t, err)
+	require.InDelta(t, 3.33, actual, 1e-12)
+	require.NoError(t, limitedErr)
+	require.InDelta(t, 19.8, limited, 1e-12)
+	require.ErrorIs(t, invalidErr, capitalcom.ErrInvalidRisk)
+}
+
+func TestMarginCalculator_ReturnsErrorForUnknownMargin(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	market := marginMarket()
+	market.Instrument.MarginFactor = 0
+
+	underTest := capitalcom.NewMarginCalculator(market, capitalcom.Preferences{})
+
+	// Act
+	_, err := underTest.RequiredMargin(capitalcom.OpenPositionRequest{Direction: capitalcom.PositionDirectionBuy, Size: 1})
+
+	// Assert
+	require.ErrorIs(t, err, capitalcom.ErrUnknownMargin)
+}
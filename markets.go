@@ -106,6 +106,15 @@ func (m *markets) Details(ctx context.Context, params DetailsParams) ([]Market,
 	return res.payload.Markets, nil
 }
 
+// Types of the instruments, see Instrument.Type.
+const (
+	InstrumentTypeShares           = "SHARES"
+	InstrumentTypeCurrencies       = "CURRENCIES"
+	InstrumentTypeIndices          = "INDICES"
+	InstrumentTypeCryptocurrencies = "CRYPTOCURRENCIES"
+	InstrumentTypeCommodities      = "COMMODITIES"
+)
+
 type (
 	Instrument struct {
 		Epic                     string       `json:"epic"`
@@ -18,6 +18,14 @@ const (
 	StopOrder  OrderType = "STOP"
 )
 
+// TimeInForce defines how long a working order stays active.
+type TimeInForce string
+
+const (
+	TimeInForceGoodTillCancelled TimeInForce = "GOOD_TILL_CANCELLED"
+	TimeInForceGoodTillDate      TimeInForce = "GOOD_TILL_DATE"
+)
+
 type (
 	workingOrdersResponsePayload struct {
 		WorkingOrders []WorkingOrderDetail `json:"workingOrders"`
@@ -178,14 +186,18 @@ type (
 	}
 
 	// UpdateOrderRequest represents the payload to update an existing order.
-	// todo: make validation or a builder that won't allow to create an invalid instance
+	// NewLimitOrder and NewStopOrder build a valid CreateOrderRequest, see also Validator.
 	UpdateOrderRequest struct {
 		// Level - the order price
 		Level float64 `json:"level"`
 
-		// GoodTillDate - order cancellation date in UTC time
+		// GoodTillDate - order cancellation date, it is sent in UTC time
 		GoodTillDate time.Time `json:"-"`
 
+		// TimeInForce is optional, an order is good till GoodTillDate if it is set and good till cancelled otherwise.
+		// If it is set explicitly, GoodTillDate is required for GOOD_TILL_DATE and not allowed for GOOD_TILL_CANCELLED.
+		TimeInForce TimeInForce `json:"timeInForce,omitempty"`
+
 		// GuaranteedStop must be true if a guaranteed stop is required.
 		// - Default value: false
 		// - If GuaranteedStop equals true, then set StopLevel, StopDistance or StopAmount
@@ -220,50 +232,77 @@ type (
 	}
 )
 
-func (cr CreateOrderRequest) MarshalJSON() ([]byte, error) {
-	updateOrderRequestJSON, err := cr.UpdateOrderRequest.MarshalJSON()
-	if err != nil {
-		return nil, NewRequestPayloadEncodingError(err)
+type (
+	updateOrderRequestJSON struct {
+		Level          float64     `json:"level"`
+		GoodTillDate   string      `json:"goodTillDate,omitempty"`
+		TimeInForce    TimeInForce `json:"timeInForce,omitempty"`
+		GuaranteedStop bool        `json:"guaranteedStop,omitempty"`
+		TrailingStop   bool        `json:"trailingStop,omitempty"`
+		StopLevel      float64     `json:"stopLevel,omitempty"`
+		StopDistance   float64     `json:"stopDistance,omitempty"`
+		StopAmount     float64     `json:"stopAmount,omitempty"`
+		ProfitLevel    float64     `json:"profitLevel,omitempty"`
+		ProfitDistance float64     `json:"profitDistance,omitempty"`
+		ProfitAmount   float64     `json:"profitAmount,omitempty"`
 	}
 
-	aux := struct {
+	createOrderRequestJSON struct {
 		Direction PositionDirection `json:"direction"`
 		Epic      string            `json:"epic"`
 		Size      float64           `json:"size"`
 		Type      OrderType         `json:"type"`
-	}{
-		Direction: cr.Direction,
-		Epic:      cr.Epic,
-		Size:      cr.Size,
-		Type:      cr.Type,
+		updateOrderRequestJSON
 	}
+)
 
-	createOrderRequestJSON, err := json.Marshal(aux)
+func (cr CreateOrderRequest) MarshalJSON() ([]byte, error) {
+	updateJSON, err := cr.UpdateOrderRequest.toJSON()
 	if err != nil {
 		return nil, NewRequestPayloadEncodingError(err)
 	}
 
-	updateOrderRequestJSON[0] = ','
-
-	merged := make([]byte, 0, len(createOrderRequestJSON)+len(updateOrderRequestJSON)-1)
-	merged = append(merged, createOrderRequestJSON[:len(createOrderRequestJSON)-1]...)
-	merged = append(merged, updateOrderRequestJSON...)
+	data, err := json.Marshal(createOrderRequestJSON{
+		Direction:              cr.Direction,
+		Epic:                   cr.Epic,
+		Size:                   cr.Size,
+		Type:                   cr.Type,
+		updateOrderRequestJSON: updateJSON,
+	})
+	if err != nil {
+		return nil, NewRequestPayloadEncodingError(err)
+	}
 
-	return merged, nil
+	return data, nil
 }
 
-func (ur UpdateOrderRequest) MarshalJSON() ([]byte, error) {
-	type alias UpdateOrderRequest
+func (cr *CreateOrderRequest) UnmarshalJSON(data []byte) error {
+	aux := createOrderRequestJSON{}
 
-	aux := struct {
-		GoodTillDateString string `json:"goodTillDate,omitempty"`
-		alias
-	}{
-		alias: alias(ur),
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return NewResponsePayloadDecodingError(err)
 	}
 
-	if !ur.GoodTillDate.IsZero() {
-		aux.GoodTillDateString = ur.GoodTillDate.Format(dateFormat)
+	update, err := aux.updateOrderRequestJSON.toRequest()
+	if err != nil {
+		return NewResponsePayloadDecodingError(err)
+	}
+
+	*cr = CreateOrderRequest{
+		Direction:          aux.Direction,
+		Epic:               aux.Epic,
+		Size:               aux.Size,
+		Type:               aux.Type,
+		UpdateOrderRequest: update,
+	}
+
+	return nil
+}
+
+func (ur UpdateOrderRequest) MarshalJSON() ([]byte, error) {
+	aux, err := ur.toJSON()
+	if err != nil {
+		return nil, NewRequestPayloadEncodingError(err)
 	}
 
 	data, err := json.Marshal(aux)
@@ -274,6 +313,97 @@ func (ur UpdateOrderRequest) MarshalJSON() ([]byte, error) {
 	return data, nil
 }
 
+func (ur *UpdateOrderRequest) UnmarshalJSON(data []byte) error {
+	aux := updateOrderRequestJSON{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return NewResponsePayloadDecodingError(err)
+	}
+
+	update, err := aux.toRequest()
+	if err != nil {
+		return NewResponsePayloadDecodingError(err)
+	}
+
+	*ur = update
+
+	return nil
+}
+
+func (ur UpdateOrderRequest) toJSON() (updateOrderRequestJSON, error) {
+	aux := updateOrderRequestJSON{
+		Level:          ur.Level,
+		TimeInForce:    ur.TimeInForce,
+		GuaranteedStop: ur.GuaranteedStop,
+		TrailingStop:   ur.TrailingStop,
+		StopLevel:      ur.StopLevel,
+		StopDistance:   ur.StopDistance,
+		StopAmount:     ur.StopAmount,
+		ProfitLevel:    ur.ProfitLevel,
+		ProfitDistance: ur.ProfitDistance,
+		ProfitAmount:   ur.ProfitAmount,
+	}
+
+	switch {
+	case ur.TimeInForce == TimeInForceGoodTillDate && ur.GoodTillDate.IsZero():
+		return aux, ErrGoodTillDateRequired
+	case ur.TimeInForce == TimeInForceGoodTillCancelled && !ur.GoodTillDate.IsZero():
+		return aux, ErrGoodTillDateNotAllowed
+	}
+
+	if !ur.GoodTillDate.IsZero() {
+		aux.GoodTillDate = ur.GoodTillDate.UTC().Format(dateFormat)
+	}
+
+	return aux, nil
+}
+
+func (aux updateOrderRequestJSON) toRequest() (UpdateOrderRequest, error) {
+	ur := UpdateOrderRequest{
+		Level:          aux.Level,
+		TimeInForce:    aux.TimeInForce,
+		GuaranteedStop: aux.GuaranteedStop,
+		TrailingStop:   aux.TrailingStop,
+		StopLevel:      aux.StopLevel,
+		StopDistance:   aux.StopDistance,
+		StopAmount:     aux.StopAmount,
+		ProfitLevel:    aux.ProfitLevel,
+		ProfitDistance: aux.ProfitDistance,
+		ProfitAmount:   aux.ProfitAmount,
+	}
+
+	if aux.GoodTillDate != "" {
+		// the date is sent in UTC without a zone
+		goodTillDate, err := time.ParseInLocation(dateFormat, aux.GoodTillDate, time.UTC)
+		if err != nil {
+			return ur, err
+		}
+
+		ur.GoodTillDate = goodTillDate
+	}
+
+	return ur, nil
+}
+
+// TimeInForceValue returns the time in force of the working order as TimeInForce.
+func (a WorkingOrderData) TimeInForceValue() TimeInForce {
+	return TimeInForce(a.TimeInForce)
+}
+
+// UpdateRequest returns a request updating the working order with its current parameters, so it can be
+// modified and sent with Orders.Update.
+func (a WorkingOrderData) UpdateRequest() UpdateOrderRequest {
+	return UpdateOrderRequest{
+		Level:          a.OrderLevel,
+		GoodTillDate:   a.GoodTillDateUTC,
+		TimeInForce:    a.TimeInForceValue(),
+		GuaranteedStop: a.GuaranteedStop,
+		TrailingStop:   a.TrailingStop,
+		StopDistance:   a.StopDistance,
+		ProfitDistance: a.ProfitDistance,
+	}
+}
+
 func (o *orders) Create(ctx context.Context, req CreateOrderRequest) (string, error) {
 	headers := o.tokens.headers()
 
@@ -52,3 +52,141 @@ func Test_CreateOrderRequestMarshalSuccessfully(t *testing.T) {
 	require.NoError(t, err)
 	require.JSONEq(t, expectedJSON, string(actualJSON))
 }
+
+func Test_UpdateOrderRequestMarshalsGoodTillDateInUTC(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.UpdateOrderRequest{
+		Level:        103263.45,
+		GoodTillDate: time.Date(2025, 3, 5, 15, 23, 43, 0, time.FixedZone("UTC+3", 3*60*60)),
+		TimeInForce:  capitalcom.TimeInForceGoodTillDate,
+	}
+
+	expectedJSON := `{
+"level": 103263.45,
+"goodTillDate": "2025-03-05T12:23:43",
+"timeInForce": "GOOD_TILL_DATE"
+}`
+
+	// Act
+	actualJSON, err := json.Marshal(underTest)
+
+	// Assert
+	require.NoError(t, err)
+	require.JSONEq(t, expectedJSON, string(actualJSON))
+}
+
+func Test_UpdateOrderRequestMarshalReturnsErrorForInconsistentTimeInForce(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		underTest capitalcom.UpdateOrderRequest
+		expected  error
+	}{
+		{
+			name: "good till date without date",
+			underTest: capitalcom.UpdateOrderRequest{
+				Level:       103263.45,
+				TimeInForce: capitalcom.TimeInForceGoodTillDate,
+			},
+			expected: capitalcom.ErrGoodTillDateRequired,
+		},
+		{
+			name: "good till cancelled with date",
+			underTest: capitalcom.UpdateOrderRequest{
+				Level:        103263.45,
+				GoodTillDate: time.Date(2025, 3, 5, 12, 23, 43, 0, time.UTC),
+				TimeInForce:  capitalcom.TimeInForceGoodTillCancelled,
+			},
+			expected: capitalcom.ErrGoodTillDateNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := json.Marshal(capitalcom.CreateOrderRequest{UpdateOrderRequest: tt.underTest})
+
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func Test_CreateOrderRequestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	expected := capitalcom.CreateOrderRequest{
+		Direction: capitalcom.PositionDirectionSell,
+		Epic:      "BTCUSD",
+		Size:      0.0034,
+		Type:      capitalcom.LimitOrder,
+		UpdateOrderRequest: capitalcom.UpdateOrderRequest{
+			Level:          103263.45,
+			GoodTillDate:   time.Date(2025, 3, 5, 12, 23, 43, 0, time.UTC),
+			TimeInForce:    capitalcom.TimeInForceGoodTillDate,
+			TrailingStop:   true,
+			StopDistance:   150,
+			ProfitDistance: 300,
+		},
+	}
+
+	data, err := json.Marshal(expected)
+	require.NoError(t, err)
+
+	actual := capitalcom.CreateOrderRequest{}
+
+	// Act
+	err = json.Unmarshal(data, &actual)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func Test_WorkingOrderDataUpdateRequest(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	workingOrder := capitalcom.WorkingOrderData{}
+
+	err := json.Unmarshal([]byte(`{
+"dealId": "006011e7-0001-54c4-0000-000080560043",
+"direction": "BUY",
+"epic": "SILVER",
+"orderSize": 1,
+"leverage": 20,
+"orderLevel": 23.5,
+"timeInForce": "GOOD_TILL_DATE",
+"goodTillDate": "2025-03-05T14:23:43",
+"goodTillDateUTC": "2025-03-05T12:23:43",
+"createdDate": "2025-03-01T10:00:00",
+"createdDateUTC": "2025-03-01T08:00:00",
+"guaranteedStop": false,
+"orderType": "LIMIT",
+"stopDistance": 1.5,
+"profitDistance": 3,
+"trailingStop": false,
+"currencyCode": "USD"
+}`), &workingOrder)
+	require.NoError(t, err)
+
+	expectedJSON := `{
+"level": 23.5,
+"goodTillDate": "2025-03-05T12:23:43",
+"timeInForce": "GOOD_TILL_DATE",
+"stopDistance": 1.5,
+"profitDistance": 3
+}`
+
+	// Act
+	actualJSON, err := json.Marshal(workingOrder.UpdateRequest())
+
+	// Assert
+	require.NoError(t, err)
+	require.JSONEq(t, expectedJSON, string(actualJSON))
+	require.Equal(t, capitalcom.TimeInForceGoodTillDate, workingOrder.TimeInForceValue())
+}
@@ -0,0 +1,189 @@
+// Package candles resamples the price history bars into arbitrary timeframes, e.g. 2 hours, 3 minutes or a month,
+// and derives mid-price and spread series from them.
+package candles
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gromson/capitalcom"
+)
+
+var ErrInvalidTimeframe = errors.New("invalid timeframe")
+
+const day = 24 * time.Hour
+
+// Timeframe is a duration of the resampled bars, see Every, Days, Weeks and Months.
+type Timeframe struct {
+	duration time.Duration
+	days     int
+	weeks    int
+	months   int
+}
+
+// Every returns an intraday timeframe, the duration must be positive and not longer than a day.
+// Bars are aligned to the beginning of the session day, so a duration not dividing the day results
+// in a shorter last bar of the day.
+func Every(duration time.Duration) Timeframe {
+	return Timeframe{duration: duration}
+}
+
+// Days returns a timeframe of n session days, the bars are aligned to multiples of n days since the Unix epoch.
+func Days(n int) Timeframe {
+	return Timeframe{days: n}
+}
+
+// Weeks returns a timeframe of n weeks starting on Monday.
+func Weeks(n int) Timeframe {
+	return Timeframe{weeks: n}
+}
+
+// Months returns a timeframe of n calendar months, the bars are aligned to the beginning of the year.
+func Months(n int) Timeframe {
+	return Timeframe{months: n}
+}
+
+func (tf Timeframe) validate() error {
+	set := 0
+
+	for _, n := range []int{int(tf.duration), tf.days, tf.weeks, tf.months} {
+		if n < 0 {
+			return ErrInvalidTimeframe
+		}
+
+		if n > 0 {
+			set++
+		}
+	}
+
+	if set != 1 || tf.duration > day {
+		return ErrInvalidTimeframe
+	}
+
+	return nil
+}
+
+// Option is a functional option of the resampling.
+type Option func(*config)
+
+type config struct {
+	location     *time.Location
+	sessionStart time.Duration
+}
+
+// WithLocation aligns the bars to the days in the location, e.g. the exchange time zone. UTC is used by default.
+func WithLocation(location *time.Location) Option {
+	return func(c *config) {
+		c.location = location
+	}
+}
+
+// WithSessionStart shifts the beginning of the session day from midnight, e.g. 9h30m for a session
+// opening at 09:30 in the location, or -2h for a session starting at 22:00 of the previous day.
+func WithSessionStart(offset time.Duration) Option {
+	return func(c *config) {
+		c.sessionStart = offset
+	}
+}
+
+// Resample aggregates the bars into the timeframe. Bid and ask prices are aggregated separately:
+// the open of the first bar, the highest high, the lowest low and the close of the last bar,
+// LastTradedVolume is summed. SnapshotTimeUTC of a resampled bar is the beginning of its period,
+// SnapshotTime is shifted by the same offset from the UTC time as in the source bars.
+// Periods without source bars are skipped.
+func Resample(prices []capitalcom.Price, timeframe Timeframe, opts ...Option) ([]capitalcom.Price, error) {
+	if err := timeframe.validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := config{location: time.UTC}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	sorted := slices.Clone(prices)
+	slices.SortStableFunc(sorted, func(a, b capitalcom.Price) int {
+		return a.SnapshotTimeUTC.Compare(b.SnapshotTimeUTC)
+	})
+
+	var result []capitalcom.Price
+
+	for _, price := range sorted {
+		start := cfg.periodStart(timeframe, price.SnapshotTimeUTC)
+
+		if n := len(result); n > 0 && result[n-1].SnapshotTimeUTC.Equal(start) {
+			merge(&result[n-1], price)
+
+			continue
+		}
+
+		bar := price
+		bar.SnapshotTimeUTC = start
+		bar.SnapshotTime = price.SnapshotTime.Add(start.Sub(price.SnapshotTimeUTC))
+
+		result = append(result, bar)
+	}
+
+	return result, nil
+}
+
+func merge(bar *capitalcom.Price, price capitalcom.Price) {
+	bar.HighPrice.Bid = max(bar.HighPrice.Bid, price.HighPrice.Bid)
+	bar.HighPrice.Ask = max(bar.HighPrice.Ask, price.HighPrice.Ask)
+	bar.LowPrice.Bid = min(bar.LowPrice.Bid, price.LowPrice.Bid)
+	bar.LowPrice.Ask = min(bar.LowPrice.Ask, price.LowPrice.Ask)
+	bar.ClosePrice = price.ClosePrice
+	bar.LastTradedVolume += price.LastTradedVolume
+}
+
+// periodStart returns the beginning of the timeframe period the time belongs to, in UTC.
+func (c config) periodStart(timeframe Timeframe, t time.Time) time.Time {
+	year, month, date := c.sessionDate(t)
+
+	switch {
+	case timeframe.duration > 0:
+		sessionStart := c.sessionStartOf(year, month, date)
+
+		return sessionStart.Add(t.Sub(sessionStart) / timeframe.duration * timeframe.duration).UTC()
+	case timeframe.days > 0:
+		days := floorDiv(daysSinceEpoch(year, month, date), timeframe.days) * timeframe.days
+
+		return c.sessionStartOf(1970, time.January, 1+days).UTC() //nolint:mnd
+	case timeframe.weeks > 0:
+		// the Unix epoch is Thursday, so the weeks are counted from Monday, 1969-12-29
+		weekDays := 7 * timeframe.weeks                                            //nolint:mnd
+		days := floorDiv(daysSinceEpoch(year, month, date)+3, weekDays) * weekDays //nolint:mnd
+
+		return c.sessionStartOf(1969, time.December, 29+days).UTC() //nolint:mnd
+	default:
+		months := year*12 + int(month) - 1 //nolint:mnd
+		months = floorDiv(months, timeframe.months) * timeframe.months
+
+		return c.sessionStartOf(months/12, time.Month(months%12+1), 1).UTC() //nolint:mnd
+	}
+}
+
+// sessionDate returns the date of the session day the time belongs to.
+func (c config) sessionDate(t time.Time) (int, time.Month, int) {
+	return t.In(c.location).Add(-c.sessionStart).Date()
+}
+
+// sessionStartOf returns the beginning of the session day of the date, the date is normalized like in time.Date.
+func (c config) sessionStartOf(year int, month time.Month, date int) time.Time {
+	return time.Date(year, month, date, 0, 0, 0, 0, c.location).Add(c.sessionStart)
+}
+
+func daysSinceEpoch(year int, month time.Month, date int) int {
+	return int(time.Date(year, month, date, 0, 0, 0, 0, time.UTC).Unix() / int64(day/time.Second))
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+
+	return q
+}
@@ -0,0 +1,167 @@
+package candles_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/gromson/capitalcom/pkg/candles"
+	"github.com/stretchr/testify/require"
+)
+
+// bar creates a bar with the bid prices from the OHLC values and the ask prices higher by 1.
+func bar(snapshot time.Time, o, h, l, c float64, volume int) capitalcom.Price {
+	return capitalcom.Price{
+		SnapshotTime:     snapshot.Add(2 * time.Hour),
+		SnapshotTimeUTC:  snapshot,
+		OpenPrice:        capitalcom.PriceData{Bid: o, Ask: o + 1},
+		HighPrice:        capitalcom.PriceData{Bid: h, Ask: h + 1},
+		LowPrice:         capitalcom.PriceData{Bid: l, Ask: l + 1},
+		ClosePrice:       capitalcom.PriceData{Bid: c, Ask: c + 1},
+		LastTradedVolume: volume,
+	}
+}
+
+func TestResample_Intraday(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	start := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+
+	prices := []capitalcom.Price{
+		bar(start.Add(time.Hour), 12, 14, 11, 13, 2),
+		bar(start, 10, 12, 9, 11, 1),
+		bar(start.Add(2*time.Hour), 13, 16, 12, 15, 3),
+		bar(start.Add(3*time.Hour), 15, 15, 8, 9, 4),
+	}
+
+	// Act
+	actual, err := candles.Resample(prices, candles.Every(2*time.Hour))
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []capitalcom.Price{
+		bar(start, 10, 14, 9, 13, 3),
+		bar(start.Add(2*time.Hour), 13, 16, 8, 9, 7),
+	}, actual)
+}
+
+func TestResample_AlignsToSession(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 09:30 in New York is 13:30 UTC in summer
+	sessionStart := time.Date(2026, 6, 22, 13, 30, 0, 0, time.UTC)
+
+	prices := []capitalcom.Price{
+		bar(sessionStart.Add(-time.Hour), 1, 1, 1, 1, 1),
+		bar(sessionStart, 2, 2, 2, 2, 1),
+		bar(sessionStart.Add(23*time.Hour), 3, 3, 3, 3, 1),
+		bar(sessionStart.Add(24*time.Hour), 4, 4, 4, 4, 1),
+	}
+
+	// Act
+	actual, err := candles.Resample(prices, candles.Days(1),
+		candles.WithLocation(newYork), candles.WithSessionStart(9*time.Hour+30*time.Minute))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, actual, 3)
+	require.Equal(t, sessionStart.Add(-24*time.Hour), actual[0].SnapshotTimeUTC)
+	require.Equal(t, sessionStart, actual[1].SnapshotTimeUTC)
+	require.Equal(t, sessionStart.Add(2*time.Hour), actual[1].SnapshotTime)
+	require.InDelta(t, 3, actual[1].ClosePrice.Bid, 1e-9)
+	require.Equal(t, 2, actual[1].LastTradedVolume)
+	require.Equal(t, sessionStart.Add(24*time.Hour), actual[2].SnapshotTimeUTC)
+}
+
+func TestResample_CalendarTimeframes(t *testing.T) {
+	t.Parallel()
+
+	prices := []capitalcom.Price{
+		bar(time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC), 1, 1, 1, 1, 1),
+		bar(time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), 2, 2, 2, 2, 1),
+		bar(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), 3, 3, 3, 3, 1),
+	}
+
+	tests := []struct {
+		name      string
+		timeframe candles.Timeframe
+		expected  []time.Time
+	}{
+		{
+			name:      "weeks",
+			timeframe: candles.Weeks(1),
+			expected: []time.Time{
+				time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:      "month",
+			timeframe: candles.Months(1),
+			expected: []time.Time{
+				time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:      "quarter",
+			timeframe: candles.Months(3),
+			expected: []time.Time{
+				time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			actual, err := candles.Resample(prices, tt.timeframe)
+
+			// Assert
+			require.NoError(t, err)
+
+			var actualTimes []time.Time
+			for _, price := range actual {
+				actualTimes = append(actualTimes, price.SnapshotTimeUTC)
+			}
+
+			require.Equal(t, tt.expected, actualTimes)
+		})
+	}
+}
+
+func TestResample_ReturnsErrorForInvalidTimeframe(t *testing.T) {
+	t.Parallel()
+
+	for _, timeframe := range []candles.Timeframe{{}, candles.Every(25 * time.Hour), candles.Months(-1)} {
+		_, err := candles.Resample(nil, timeframe)
+
+		require.ErrorIs(t, err, candles.ErrInvalidTimeframe)
+	}
+}
+
+func TestMidAndSpreadSeries(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	start := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	prices := []capitalcom.Price{bar(start, 10, 12, 9, 11, 1)}
+
+	// Act
+	mid := candles.MidSeries(prices)
+	spread := candles.SpreadSeries(prices)
+
+	// Assert
+	require.Equal(t, []candles.OHLC{{Time: start, Open: 10.5, High: 12.5, Low: 9.5, Close: 11.5}}, mid)
+	require.Equal(t, []candles.Point{{Time: start, Value: 1}}, spread)
+}
@@ -0,0 +1,62 @@
+package candles
+
+import (
+	"time"
+
+	"github.com/gromson/capitalcom"
+)
+
+// OHLC is a bar of a single price, e.g. of the mid-price.
+type OHLC struct {
+	Time  time.Time
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+// Point is a value of a series at the time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Mid returns the mid-price between the bid and ask prices.
+func Mid(data capitalcom.PriceData) float64 {
+	return (data.Bid + data.Ask) / 2 //nolint:mnd
+}
+
+// Spread returns the difference between the ask and bid prices.
+func Spread(data capitalcom.PriceData) float64 {
+	return data.Ask - data.Bid
+}
+
+// MidSeries returns the mid-price bars of the bars, the time of a bar is its SnapshotTimeUTC.
+// The high and low are the mid-prices of the bid and ask highs and lows, which are the best
+// approximation available as the bid and ask extremes may happen at different moments.
+func MidSeries(prices []capitalcom.Price) []OHLC {
+	series := make([]OHLC, 0, len(prices))
+
+	for _, price := range prices {
+		series = append(series, OHLC{
+			Time:  price.SnapshotTimeUTC,
+			Open:  Mid(price.OpenPrice),
+			High:  Mid(price.HighPrice),
+			Low:   Mid(price.LowPrice),
+			Close: Mid(price.ClosePrice),
+		})
+	}
+
+	return series
+}
+
+// SpreadSeries returns the spread at the close of every bar, the time of a point is the SnapshotTimeUTC of the bar.
+func SpreadSeries(prices []capitalcom.Price) []Point {
+	series := make([]Point, 0, len(prices))
+
+	for _, price := range prices {
+		series = append(series, Point{Time: price.SnapshotTimeUTC, Value: Spread(price.ClosePrice)})
+	}
+
+	return series
+}
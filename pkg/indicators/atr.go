@@ -0,0 +1,54 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/gromson/capitalcom/pkg/candles"
+)
+
+// ATR is the average true range with the Wilder's smoothing, the true range of the first bar is its high-low range.
+type ATR struct {
+	state atr
+}
+
+// NewATR creates an average true range of the period, 14 is the common one.
+func NewATR(period int) (*ATR, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	return &ATR{state: atr{period: period}}, nil
+}
+
+func (a *ATR) Add(bar candles.OHLC) (float64, bool) {
+	a.state = a.state.next(bar)
+
+	return a.state.average.value, a.state.average.count >= a.state.period
+}
+
+func (a *ATR) Peek(bar candles.OHLC) (float64, bool) {
+	state := a.state.next(bar)
+
+	return state.average.value, state.average.count >= state.period
+}
+
+type atr struct {
+	period    int
+	prevClose float64
+	hasPrev   bool
+	average   wilder
+}
+
+func (s atr) next(bar candles.OHLC) atr {
+	trueRange := bar.High - bar.Low
+
+	if s.hasPrev {
+		trueRange = max(trueRange, math.Abs(bar.High-s.prevClose), math.Abs(bar.Low-s.prevClose))
+	}
+
+	s.average = s.average.next(s.period, trueRange)
+	s.prevClose = bar.Close
+	s.hasPrev = true
+
+	return s
+}
@@ -0,0 +1,131 @@
+package indicators
+
+import "github.com/gromson/capitalcom/pkg/candles"
+
+// SMA is a simple moving average of the close prices.
+type SMA struct {
+	window *window
+}
+
+// NewSMA creates a simple moving average of the period.
+func NewSMA(period int) (*SMA, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	return &SMA{window: newWindow(period)}, nil
+}
+
+func (s *SMA) Add(bar candles.OHLC) (float64, bool) {
+	s.window.push(bar.Close)
+
+	return s.window.mean(), s.window.full()
+}
+
+func (s *SMA) Peek(bar candles.OHLC) (float64, bool) {
+	w := s.window.peek(bar.Close)
+
+	return w.mean(), w.full()
+}
+
+// EMA is an exponential moving average of the close prices, it is seeded with the simple average
+// of the first period bars.
+type EMA struct {
+	state ema
+}
+
+// NewEMA creates an exponential moving average of the period.
+func NewEMA(period int) (*EMA, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	return &EMA{state: ema{period: period}}, nil
+}
+
+func (e *EMA) Add(bar candles.OHLC) (float64, bool) {
+	e.state = e.state.next(bar.Close)
+
+	return e.state.value, e.state.ready()
+}
+
+func (e *EMA) Peek(bar candles.OHLC) (float64, bool) {
+	state := e.state.next(bar.Close)
+
+	return state.value, state.ready()
+}
+
+// ema is a state of an exponential moving average, the next state is calculated without changing the current one.
+type ema struct {
+	period int
+	count  int
+	sum    float64
+	value  float64
+}
+
+func (s ema) next(value float64) ema {
+	s.count++
+
+	if s.count <= s.period {
+		s.sum += value
+		s.value = s.sum / float64(s.count)
+
+		return s
+	}
+
+	alpha := 2 / float64(s.period+1) //nolint:mnd
+	s.value += alpha * (value - s.value)
+
+	return s
+}
+
+func (s ema) ready() bool {
+	return s.count >= s.period
+}
+
+// window holds the last values up to the period in a ring buffer.
+type window struct {
+	values []float64
+	start  int
+	sum    float64
+}
+
+func newWindow(period int) *window {
+	return &window{values: make([]float64, 0, period)}
+}
+
+func (w *window) push(value float64) {
+	if len(w.values) < cap(w.values) {
+		w.values = append(w.values, value)
+		w.sum += value
+
+		return
+	}
+
+	w.sum += value - w.values[w.start]
+	w.values[w.start] = value
+	w.start = (w.start + 1) % len(w.values)
+}
+
+// peek returns a copy of the window with the value pushed.
+func (w *window) peek(value float64) *window {
+	values := make([]float64, len(w.values), cap(w.values))
+	copy(values, w.values)
+
+	peeked := &window{values: values, start: w.start, sum: w.sum}
+	peeked.push(value)
+
+	return peeked
+}
+
+func (w *window) full() bool {
+	return len(w.values) == cap(w.values)
+}
+
+func (w *window) mean() float64 {
+	if len(w.values) == 0 {
+		return 0
+	}
+
+	return w.sum / float64(len(w.values))
+}
@@ -0,0 +1,64 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/gromson/capitalcom/pkg/candles"
+)
+
+// BollingerValue is a value of the Bollinger bands.
+type BollingerValue struct {
+	Middle float64
+	Upper  float64
+	Lower  float64
+}
+
+// Bollinger is the Bollinger bands of the close prices: the simple moving average and the bands
+// the number of the population standard deviations away from it.
+type Bollinger struct {
+	window     *window
+	deviations float64
+}
+
+// NewBollinger creates the Bollinger bands of the period, 20 periods and 2 deviations are the common ones.
+func NewBollinger(period int, deviations float64) (*Bollinger, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	return &Bollinger{window: newWindow(period), deviations: deviations}, nil
+}
+
+func (b *Bollinger) Add(bar candles.OHLC) (BollingerValue, bool) {
+	b.window.push(bar.Close)
+
+	return b.value(b.window), b.window.full()
+}
+
+func (b *Bollinger) Peek(bar candles.OHLC) (BollingerValue, bool) {
+	w := b.window.peek(bar.Close)
+
+	return b.value(w), w.full()
+}
+
+func (b *Bollinger) value(w *window) BollingerValue {
+	mean := w.mean()
+
+	var variance float64
+
+	for _, value := range w.values {
+		variance += (value - mean) * (value - mean)
+	}
+
+	if len(w.values) > 0 {
+		variance /= float64(len(w.values))
+	}
+
+	deviation := b.deviations * math.Sqrt(variance)
+
+	return BollingerValue{
+		Middle: mean,
+		Upper:  mean + deviation,
+		Lower:  mean - deviation,
+	}
+}
@@ -0,0 +1,108 @@
+// Package indicators implements technical indicators over the price history bars and live candle updates.
+// The indicators keep a small incremental state, so an indicator can be warmed up with the price history
+// and then fed with the bars one at a time, see Live.
+package indicators
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/gromson/capitalcom/pkg/candles"
+)
+
+var ErrInvalidPeriod = errors.New("the period must be positive")
+
+// Source is a price of a bar the indicators are calculated from.
+type Source int
+
+const (
+	SourceBid Source = iota
+	SourceAsk
+	SourceMid
+)
+
+// Indicator is calculated incrementally bar by bar. Add moves the indicator to the next bar,
+// Peek returns the value the indicator would have after adding the bar without changing its state,
+// which is useful for the still-forming bar. Both return false until the indicator has enough bars.
+type Indicator[T any] interface {
+	Add(bar candles.OHLC) (T, bool)
+	Peek(bar candles.OHLC) (T, bool)
+}
+
+// Bar returns the OHLC prices of the source, the time of the bar is the SnapshotTimeUTC of the price.
+func Bar(price capitalcom.Price, source Source) candles.OHLC {
+	pick := func(data capitalcom.PriceData) float64 {
+		switch source {
+		case SourceAsk:
+			return data.Ask
+		case SourceMid:
+			return candles.Mid(data)
+		default:
+			return data.Bid
+		}
+	}
+
+	return candles.OHLC{
+		Time:  price.SnapshotTimeUTC,
+		Open:  pick(price.OpenPrice),
+		High:  pick(price.HighPrice),
+		Low:   pick(price.LowPrice),
+		Close: pick(price.ClosePrice),
+	}
+}
+
+// Sample is a value of an indicator at the time of a bar.
+type Sample[T any] struct {
+	Time  time.Time
+	Value T
+}
+
+// Series adds the bars to the indicator and returns its values for the bars it has enough data for.
+func Series[T any](indicator Indicator[T], prices []capitalcom.Price, source Source) []Sample[T] {
+	series := make([]Sample[T], 0, len(prices))
+
+	for _, price := range prices {
+		if value, ok := indicator.Add(Bar(price, source)); ok {
+			series = append(series, Sample[T]{Time: price.SnapshotTimeUTC, Value: value})
+		}
+	}
+
+	return series
+}
+
+// Live feeds an indicator with bars which can be updated until they are complete, like the price history
+// followed by the real-time candles of the stream, see Stream.Candles. A bar is added to the indicator
+// when a bar of a later time arrives, until then the updates of the bar replace each other.
+// Live is not safe for concurrent use.
+type Live[T any] struct {
+	indicator Indicator[T]
+	source    Source
+
+	forming    candles.OHLC
+	hasForming bool
+}
+
+// NewLive creates a live feed of the indicator.
+func NewLive[T any](indicator Indicator[T], source Source) *Live[T] {
+	return &Live[T]{indicator: indicator, source: source}
+}
+
+// Update adds the bar, or replaces the forming bar of the same time, and returns the current value
+// of the indicator including the forming bar. The updates of the bars older than the forming one are ignored.
+func (l *Live[T]) Update(price capitalcom.Price) (T, bool) {
+	bar := Bar(price, l.source)
+
+	if l.hasForming && bar.Time.Before(l.forming.Time) {
+		return l.indicator.Peek(l.forming)
+	}
+
+	if l.hasForming && bar.Time.After(l.forming.Time) {
+		l.indicator.Add(l.forming)
+	}
+
+	l.forming = bar
+	l.hasForming = true
+
+	return l.indicator.Peek(bar)
+}
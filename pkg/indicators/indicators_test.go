@@ -0,0 +1,216 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/gromson/capitalcom/pkg/candles"
+	"github.com/gromson/capitalcom/pkg/indicators"
+	"github.com/stretchr/testify/require"
+)
+
+const delta = 1e-9
+
+var start = time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals
+
+func closes(values ...float64) []candles.OHLC {
+	bars := make([]candles.OHLC, 0, len(values))
+
+	for i, value := range values {
+		bars = append(bars, candles.OHLC{
+			Time:  start.Add(time.Duration(i) * time.Minute),
+			Open:  value,
+			High:  value,
+			Low:   value,
+			Close: value,
+		})
+	}
+
+	return bars
+}
+
+// addAll adds the bars and returns the values the indicator is ready for.
+func addAll[T any](indicator indicators.Indicator[T], bars []candles.OHLC) []T {
+	var values []T
+
+	for _, bar := range bars {
+		if value, ok := indicator.Add(bar); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func TestSMA(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := indicators.NewSMA(3)
+	require.NoError(t, err)
+
+	// Act
+	actual := addAll[float64](underTest, closes(1, 2, 3, 4, 5))
+	peeked, ok := underTest.Peek(closes(11)[0])
+
+	// Assert
+	require.InDeltaSlice(t, []float64{2, 3, 4}, actual, delta)
+	require.True(t, ok)
+	require.InDelta(t, 20.0/3, peeked, delta)
+
+	next, ok := underTest.Add(closes(8)[0])
+	require.True(t, ok)
+	require.InDelta(t, 17.0/3, next, delta)
+}
+
+func TestEMA(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := indicators.NewEMA(3)
+	require.NoError(t, err)
+
+	// Act
+	actual := addAll[float64](underTest, closes(1, 2, 3, 4, 5))
+
+	// Assert
+	require.InDeltaSlice(t, []float64{2, 3, 4}, actual, delta)
+}
+
+func TestRSI(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := indicators.NewRSI(2)
+	require.NoError(t, err)
+
+	// Act
+	actual := addAll[float64](underTest, closes(1, 2, 3, 2))
+
+	// Assert
+	require.InDeltaSlice(t, []float64{100, 50}, actual, delta)
+}
+
+func TestATR(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := indicators.NewATR(2)
+	require.NoError(t, err)
+
+	bars := []candles.OHLC{
+		{High: 10, Low: 8, Close: 9},
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 9, Close: 11},
+	}
+
+	// Act
+	actual := addAll[float64](underTest, bars)
+
+	// Assert
+	require.InDeltaSlice(t, []float64{2, 2.5}, actual, delta)
+}
+
+func TestMACD(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := indicators.NewMACD(2, 3, 2)
+	require.NoError(t, err)
+
+	// Act
+	actual := addAll[indicators.MACDValue](underTest, closes(1, 2, 3, 4, 5, 6))
+
+	// Assert
+	require.Len(t, actual, 3)
+
+	for _, value := range actual {
+		require.InDelta(t, 0.5, value.MACD, delta)
+		require.InDelta(t, 0.5, value.Signal, delta)
+		require.InDelta(t, 0, value.Histogram, delta)
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest, err := indicators.NewBollinger(3, 2)
+	require.NoError(t, err)
+
+	// Act
+	actual := addAll[indicators.BollingerValue](underTest, closes(1, 2, 3))
+
+	// Assert
+	require.Len(t, actual, 1)
+	require.InDelta(t, 2, actual[0].Middle, delta)
+	require.InDelta(t, 2+2*math.Sqrt(2.0/3), actual[0].Upper, delta)
+	require.InDelta(t, 2-2*math.Sqrt(2.0/3), actual[0].Lower, delta)
+}
+
+func TestNewIndicatorsReturnErrorForInvalidPeriod(t *testing.T) {
+	t.Parallel()
+
+	_, smaErr := indicators.NewSMA(0)
+	_, emaErr := indicators.NewEMA(-1)
+	_, rsiErr := indicators.NewRSI(0)
+	_, atrErr := indicators.NewATR(0)
+	_, macdErr := indicators.NewMACD(12, 0, 9)
+	_, bollingerErr := indicators.NewBollinger(0, 2)
+
+	for _, err := range []error{smaErr, emaErr, rsiErr, atrErr, macdErr, bollingerErr} {
+		require.ErrorIs(t, err, indicators.ErrInvalidPeriod)
+	}
+}
+
+func price(snapshot time.Time, closeBid float64) capitalcom.Price {
+	return capitalcom.Price{
+		SnapshotTimeUTC: snapshot,
+		ClosePrice:      capitalcom.PriceData{Bid: closeBid, Ask: closeBid + 2},
+	}
+}
+
+func TestBarAndSeries(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	prices := []capitalcom.Price{price(start, 1), price(start.Add(time.Minute), 3)}
+
+	sma, err := indicators.NewSMA(2)
+	require.NoError(t, err)
+
+	// Act
+	series := indicators.Series[float64](sma, prices, indicators.SourceMid)
+
+	// Assert
+	require.InDelta(t, 3, indicators.Bar(prices[0], indicators.SourceAsk).Close, delta)
+	require.InDelta(t, 1, indicators.Bar(prices[0], indicators.SourceBid).Close, delta)
+	require.Equal(t, []indicators.Sample[float64]{{Time: start.Add(time.Minute), Value: 3}}, series)
+}
+
+func TestLive_ReplacesFormingBar(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	sma, err := indicators.NewSMA(2)
+	require.NoError(t, err)
+
+	underTest := indicators.NewLive[float64](sma, indicators.SourceBid)
+
+	// Act
+	_, warm := underTest.Update(price(start, 1))
+	first, _ := underTest.Update(price(start.Add(time.Minute), 2))
+	updated, _ := underTest.Update(price(start.Add(time.Minute), 4))
+	next, ok := underTest.Update(price(start.Add(2*time.Minute), 6))
+	stale, _ := underTest.Update(price(start.Add(time.Minute), 100))
+
+	// Assert
+	require.False(t, warm)
+	require.InDelta(t, 1.5, first, delta)
+	require.InDelta(t, 2.5, updated, delta)
+	require.True(t, ok)
+	require.InDelta(t, 5, next, delta)
+	require.InDelta(t, 5, stale, delta)
+}
@@ -0,0 +1,65 @@
+package indicators
+
+import "github.com/gromson/capitalcom/pkg/candles"
+
+// MACDValue is a value of the moving average convergence divergence.
+type MACDValue struct {
+	MACD      float64
+	Signal    float64
+	Histogram float64
+}
+
+// MACD is the moving average convergence divergence of the close prices.
+type MACD struct {
+	fast   ema
+	slow   ema
+	signal ema
+}
+
+// NewMACD creates a moving average convergence divergence of the periods, 12, 26 and 9 are the common ones.
+func NewMACD(fastPeriod, slowPeriod, signalPeriod int) (*MACD, error) {
+	if fastPeriod <= 0 || slowPeriod <= 0 || signalPeriod <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	return &MACD{
+		fast:   ema{period: fastPeriod},
+		slow:   ema{period: slowPeriod},
+		signal: ema{period: signalPeriod},
+	}, nil
+}
+
+func (m *MACD) Add(bar candles.OHLC) (MACDValue, bool) {
+	*m = m.next(bar.Close)
+
+	return m.value(), m.signal.ready()
+}
+
+func (m *MACD) Peek(bar candles.OHLC) (MACDValue, bool) {
+	next := m.next(bar.Close)
+
+	return next.value(), next.signal.ready()
+}
+
+// next returns the next state, the signal line starts once both moving averages are ready.
+func (m *MACD) next(value float64) MACD {
+	next := *m
+	next.fast = next.fast.next(value)
+	next.slow = next.slow.next(value)
+
+	if next.fast.ready() && next.slow.ready() {
+		next.signal = next.signal.next(next.fast.value - next.slow.value)
+	}
+
+	return next
+}
+
+func (m *MACD) value() MACDValue {
+	macd := m.fast.value - m.slow.value
+
+	return MACDValue{
+		MACD:      macd,
+		Signal:    m.signal.value,
+		Histogram: macd - m.signal.value,
+	}
+}
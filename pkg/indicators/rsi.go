@@ -0,0 +1,86 @@
+package indicators
+
+import "github.com/gromson/capitalcom/pkg/candles"
+
+// RSI is the relative strength index of the close prices with the Wilder's smoothing.
+type RSI struct {
+	state rsi
+}
+
+// NewRSI creates a relative strength index of the period, 14 is the common one.
+func NewRSI(period int) (*RSI, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	return &RSI{state: rsi{period: period}}, nil
+}
+
+func (r *RSI) Add(bar candles.OHLC) (float64, bool) {
+	r.state = r.state.next(bar.Close)
+
+	return r.state.value(), r.state.ready()
+}
+
+func (r *RSI) Peek(bar candles.OHLC) (float64, bool) {
+	state := r.state.next(bar.Close)
+
+	return state.value(), state.ready()
+}
+
+type rsi struct {
+	period  int
+	prev    float64
+	hasPrev bool
+	gain    wilder
+	loss    wilder
+}
+
+func (s rsi) next(value float64) rsi {
+	if s.hasPrev {
+		change := value - s.prev
+		s.gain = s.gain.next(s.period, max(change, 0))
+		s.loss = s.loss.next(s.period, max(-change, 0))
+	}
+
+	s.prev = value
+	s.hasPrev = true
+
+	return s
+}
+
+func (s rsi) ready() bool {
+	return s.gain.count >= s.period
+}
+
+// value returns the index, it is 50 if the price hasn't changed over the period.
+func (s rsi) value() float64 {
+	switch {
+	case s.gain.value == 0 && s.loss.value == 0:
+		return 50 //nolint:mnd
+	case s.loss.value == 0:
+		return 100 //nolint:mnd
+	default:
+		return 100 - 100/(1+s.gain.value/s.loss.value) //nolint:mnd
+	}
+}
+
+// wilder is the Wilder's moving average seeded with the simple average of the first period values.
+type wilder struct {
+	count int
+	value float64
+}
+
+func (w wilder) next(period int, value float64) wilder {
+	w.count++
+
+	if w.count <= period {
+		w.value += (value - w.value) / float64(w.count)
+
+		return w
+	}
+
+	w.value += (value - w.value) / float64(period)
+
+	return w
+}
@@ -0,0 +1,203 @@
+package capitalcom
+
+import (
+	"context"
+)
+
+// Exposure is an aggregated exposure of positions and working orders. The long and short amounts are notional
+// values, size multiplied by price, in the currency of the instruments.
+type Exposure struct {
+	Long  float64
+	Short float64
+	// PendingLong and PendingShort are the notional values of the working orders at their levels
+	PendingLong  float64
+	PendingShort float64
+	// UPL is the unrealized profit and loss reported by the API
+	UPL float64
+	// PL is the unrealized profit and loss recomputed from the current prices
+	PL float64
+}
+
+// Net returns the net exposure, positive if long.
+func (e Exposure) Net() float64 {
+	return e.Long - e.Short
+}
+
+// Gross returns the sum of the long and short exposures.
+func (e Exposure) Gross() float64 {
+	return e.Long + e.Short
+}
+
+func (e *Exposure) addPosition(valuation PositionValuation) {
+	if valuation.Detail.Position.Direction == PositionDirectionSell {
+		e.Short += valuation.Notional
+	} else {
+		e.Long += valuation.Notional
+	}
+
+	e.UPL += valuation.Detail.Position.UPL
+	e.PL += valuation.PL
+}
+
+func (e *Exposure) addOrder(order WorkingOrderData) {
+	notional := order.OrderSize * order.OrderLevel
+
+	if order.Direction == PositionDirectionSell {
+		e.PendingShort += notional
+	} else {
+		e.PendingLong += notional
+	}
+}
+
+// PositionValuation is a position valued at the current market price.
+type PositionValuation struct {
+	Detail PositionDetail
+	// Price is the price the position would be closed at: the bid for long positions and the offer for short ones
+	Price float64
+	// Notional is the size multiplied by the price
+	Notional float64
+	// PL is the profit and loss of the position at the price, in the currency of the instrument
+	PL float64
+}
+
+// ValuePosition values the position at the current bid or offer of its market.
+func ValuePosition(detail PositionDetail) PositionValuation {
+	position := detail.Position
+
+	price := detail.Market.Bid
+	pl := (price - position.Level) * position.Size
+
+	if position.Direction == PositionDirectionSell {
+		price = detail.Market.Offer
+		pl = (position.Level - price) * position.Size
+	}
+
+	return PositionValuation{
+		Detail:   detail,
+		Price:    price,
+		Notional: position.Size * price,
+		PL:       pl,
+	}
+}
+
+// Portfolio is a snapshot of the open positions and working orders of an account with aggregated exposures.
+// The exposures are in the currency of the instruments, so Total is meaningful only if all instruments
+// are in the same currency, see ByCurrency.
+type Portfolio struct {
+	Account       Account
+	Positions     []PositionValuation
+	WorkingOrders []WorkingOrderDetail
+
+	ByEpic           map[string]Exposure
+	ByInstrumentType map[string]Exposure
+	ByCurrency       map[string]Exposure
+	Total            Exposure
+}
+
+// NewPortfolio values the positions and aggregates them with the working orders of the account.
+func NewPortfolio(account Account, positions []PositionDetail, workingOrders []WorkingOrderDetail) *Portfolio {
+	p := &Portfolio{
+		Account:          account,
+		Positions:        make([]PositionValuation, 0, len(positions)),
+		WorkingOrders:    workingOrders,
+		ByEpic:           make(map[string]Exposure),
+		ByInstrumentType: make(map[string]Exposure),
+		ByCurrency:       make(map[string]Exposure),
+	}
+
+	for _, detail := range positions {
+		valuation := ValuePosition(detail)
+		p.Positions = append(p.Positions, valuation)
+
+		p.update(detail.Market.Epic, detail.Market.InstrumentType, detail.Position.Currency, func(e *Exposure) {
+			e.addPosition(valuation)
+		})
+	}
+
+	for _, order := range workingOrders {
+		data := order.WorkingOrderData
+
+		p.update(data.Epic, order.MarketData.InstrumentType, data.CurrencyCode, func(e *Exposure) {
+			e.addOrder(data)
+		})
+	}
+
+	return p
+}
+
+func (p *Portfolio) update(epic, instrumentType, currency string, add func(e *Exposure)) {
+	for _, exposures := range []struct {
+		m   map[string]Exposure
+		key string
+	}{
+		{p.ByEpic, epic},
+		{p.ByInstrumentType, instrumentType},
+		{p.ByCurrency, currency},
+	} {
+		exposure := exposures.m[exposures.key]
+		add(&exposure)
+		exposures.m[exposures.key] = exposure
+	}
+
+	add(&p.Total)
+}
+
+// Equity returns the balance of the account, which already includes the profit and loss of the open positions.
+func (p *Portfolio) Equity() float64 {
+	return p.Account.Balance.Balance
+}
+
+// MarginUsed returns the part of the equity not available for new positions.
+func (p *Portfolio) MarginUsed() float64 {
+	return p.Equity() - p.Account.Balance.Available
+}
+
+// MarginUtilization returns the share of the equity used as margin, it is 0 if the equity isn't positive.
+func (p *Portfolio) MarginUtilization() float64 {
+	equity := p.Equity()
+	if equity <= 0 {
+		return 0
+	}
+
+	return p.MarginUsed() / equity
+}
+
+// Portfolio retrieves the open positions, the working orders and the current account and builds
+// a snapshot of them, see NewPortfolio.
+func (c *Client) Portfolio(ctx context.Context) (*Portfolio, error) {
+	session, err := c.Session().Details(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := c.Account().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *Account
+
+	for i := range accounts {
+		if accounts[i].AccountID == session.AccountID {
+			current = &accounts[i]
+
+			break
+		}
+	}
+
+	if current == nil {
+		return nil, NewAccountNotFoundError(session.AccountID)
+	}
+
+	positions, err := c.Positions().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workingOrders, err := c.Orders().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPortfolio(*current, positions, workingOrders), nil
+}
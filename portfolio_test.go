@@ -0,0 +1,126 @@
+package capitalcom_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPortfolio(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	account := capitalcom.Account{
+		AccountID: "ACC",
+		Currency:  "USD",
+		Balance:   capitalcom.Balance{Balance: 10500, Deposit: 10000, ProfitLoss: 500, Available: 8400},
+	}
+
+	positions := []capitalcom.PositionDetail{
+		{
+			Position: capitalcom.Position{
+				Direction: capitalcom.PositionDirectionBuy, Size: 0.5, Level: 60000, UPL: 995, Currency: "USD",
+			},
+			Market: capitalcom.Market{
+				Epic: "BTCUSD", InstrumentType: capitalcom.InstrumentTypeCryptocurrencies, Bid: 62000, Offer: 62010,
+			},
+		},
+		{
+			Position: capitalcom.Position{
+				Direction: capitalcom.PositionDirectionSell, Size: 2, Level: 3000, UPL: -495, Currency: "USD",
+			},
+			Market: capitalcom.Market{
+				Epic: "ETHUSD", InstrumentType: capitalcom.InstrumentTypeCryptocurrencies, Bid: 3240, Offer: 3250,
+			},
+		},
+	}
+
+	workingOrders := []capitalcom.WorkingOrderDetail{{
+		WorkingOrderData: capitalcom.WorkingOrderData{
+			Direction: capitalcom.PositionDirectionBuy, Epic: "EURUSD", OrderSize: 1000, OrderLevel: 1.05, CurrencyCode: "USD",
+		},
+		MarketData: capitalcom.MarketData{InstrumentType: capitalcom.InstrumentTypeCurrencies},
+	}}
+
+	// Act
+	actual := capitalcom.NewPortfolio(account, positions, workingOrders)
+
+	// Assert
+	require.Len(t, actual.Positions, 2)
+	require.InDelta(t, 62000, actual.Positions[0].Price, 1e-9)
+	require.InDelta(t, 1000, actual.Positions[0].PL, 1e-9)
+	require.InDelta(t, 3250, actual.Positions[1].Price, 1e-9)
+	require.InDelta(t, -500, actual.Positions[1].PL, 1e-9)
+
+	crypto := actual.ByInstrumentType[capitalcom.InstrumentTypeCryptocurrencies]
+	require.InDelta(t, 31000, crypto.Long, 1e-9)
+	require.InDelta(t, 6500, crypto.Short, 1e-9)
+	require.InDelta(t, 24500, crypto.Net(), 1e-9)
+	require.InDelta(t, 37500, crypto.Gross(), 1e-9)
+	require.InDelta(t, 500, crypto.UPL, 1e-9)
+	require.InDelta(t, 500, crypto.PL, 1e-9)
+
+	require.InDelta(t, 6500, actual.ByEpic["ETHUSD"].Short, 1e-9)
+	require.InDelta(t, 1050, actual.ByEpic["EURUSD"].PendingLong, 1e-9)
+	require.InDelta(t, 1050, actual.ByInstrumentType[capitalcom.InstrumentTypeCurrencies].PendingLong, 1e-9)
+	require.InDelta(t, 24500, actual.ByCurrency["USD"].Net(), 1e-9)
+	require.InDelta(t, 1050, actual.Total.PendingLong, 1e-9)
+	require.InDelta(t, 500, actual.Total.UPL, 1e-9)
+
+	require.InDelta(t, 10500, actual.Equity(), 1e-9)
+	require.InDelta(t, 2100, actual.MarginUsed(), 1e-9)
+	require.InDelta(t, 0.2, actual.MarginUtilization(), 1e-9)
+}
+
+func TestClient_Portfolio(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	responses := map[string]string{
+		"/api/v1/session": `{"accountId":"ACC2","clientId":"CLIENT","currency":"USD"}`,
+		"/api/v1/accounts": `{"accounts":[
+{"accountId":"ACC1","currency":"EUR","balance":{"balance":1,"available":1}},
+{"accountId":"ACC2","currency":"USD","balance":{"balance":1010,"deposit":1000,"profitLoss":10,"available":900}}]}`,
+		"/api/v1/positions": `{"positions":[{
+"position":{"dealId":"D1","direction":"BUY","size":1,"level":100,"upl":9,"currency":"USD",
+"createdDate":"2026-06-22T10:00:00","createdDateUTC":"2026-06-22T08:00:00"},
+"market":{"epic":"OIL_CRUDE","instrumentType":"COMMODITIES","bid":110,"offer":111,
+"updateTime":"2026-06-22T10:00:00","updateTimeUTC":"2026-06-22T08:00:00"}}]}`,
+		"/api/v1/workingorders": `{"workingOrders":[]}`,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	// Act
+	actual, err := underTest.Portfolio(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "ACC2", actual.Account.AccountID)
+	require.Len(t, actual.Positions, 1)
+	require.InDelta(t, 10, actual.Positions[0].PL, 1e-9)
+	require.InDelta(t, 110, actual.ByEpic["OIL_CRUDE"].Long, 1e-9)
+	require.InDelta(t, 110, actual.MarginUsed(), 1e-9)
+	require.Empty(t, actual.WorkingOrders)
+}
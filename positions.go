@@ -147,6 +147,7 @@ func (p *positions) List(ctx context.Context) ([]PositionDetail, error) {
 
 type (
 	// OpenPositionRequest represents the payload to open a new position.
+	// NewMarketOrder builds a valid OpenPositionRequest, see also Validator.ValidateOpenPosition.
 	OpenPositionRequest struct {
 		Direction PositionDirection `json:"direction"`
 
@@ -160,7 +161,7 @@ type (
 	}
 
 	// UpdatePositionRequest represents the payload to update a position.
-	// todo: make validation or a builder that won't allow to create an invalid instance
+	// Validator.ValidateUpdatePosition checks it against the dealing rules of the market.
 	UpdatePositionRequest struct {
 		// GuaranteedStop
 		// - Default value: false
@@ -237,14 +238,20 @@ func (p *positions) Update(ctx context.Context, dealID string, req UpdatePositio
 
 // Close closes the position for the specified deal.
 func (p *positions) Close(ctx context.Context, dealID string) error {
+	_, err := p.close(ctx, dealID)
+
+	return err
+}
+
+func (p *positions) close(ctx context.Context, dealID string) (string, error) {
 	headers := p.tokens.headers()
 
 	res, err := del[dealReferenceResponsePayload](ctx, p.Client, "/positions/"+url.PathEscape(dealID), headers)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	p.tokens.updateTokens(res.httpResponse)
 
-	return nil
+	return res.payload.DealReference, nil
 }
@@ -25,6 +25,30 @@ const (
 	ResolutionWeek     Resolution = "WEEK"
 )
 
+// Duration returns the duration of a bar of the resolution, it returns 0 for an unknown resolution.
+func (r Resolution) Duration() time.Duration {
+	switch r {
+	case ResolutionMinute:
+		return time.Minute
+	case ResolutionMinute5:
+		return 5 * time.Minute //nolint:mnd
+	case ResolutionMinute15:
+		return 15 * time.Minute //nolint:mnd
+	case ResolutionMinute30:
+		return 30 * time.Minute //nolint:mnd
+	case ResolutionHour:
+		return time.Hour
+	case ResolutionHour4:
+		return 4 * time.Hour //nolint:mnd
+	case ResolutionDay:
+		return 24 * time.Hour //nolint:mnd
+	case ResolutionWeek:
+		return 7 * 24 * time.Hour //nolint:mnd
+	default:
+		return 0
+	}
+}
+
 type PricesParams struct {
 	Resolution Resolution
 	Max        int
@@ -76,6 +100,26 @@ type (
 	}
 )
 
+// MarshalJSON encodes the price the same way the API does, so it can be decoded back with UnmarshalJSON.
+func (pr Price) MarshalJSON() ([]byte, error) {
+	type alias Price
+
+	data, err := json.Marshal(&struct {
+		SnapshotTimeString    string `json:"snapshotTime"`
+		SnapshotTimeUTCString string `json:"snapshotTimeUTC"` //nolint:tagliatelle
+		alias
+	}{
+		SnapshotTimeString:    pr.SnapshotTime.Format(dateFormat),
+		SnapshotTimeUTCString: pr.SnapshotTimeUTC.Format(dateFormat),
+		alias:                 alias(pr),
+	})
+	if err != nil {
+		return nil, NewRequestPayloadEncodingError(err)
+	}
+
+	return data, nil
+}
+
 func (pr *Price) UnmarshalJSON(data []byte) error {
 	type alias Price
 
@@ -0,0 +1,429 @@
+package capitalcom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	priceCacheDirPerm  = 0o755
+	priceCacheFilePerm = 0o644
+)
+
+// PriceCache is an on-disk cache of historical prices. Bars are stored in the directory in one JSON file per epic,
+// resolution and day, or per year for the DAY and WEEK resolutions, along with the time ranges already fetched,
+// so only the missing gaps are requested from the API. A bar is still forming until its duration has passed since
+// its snapshot time, bars don't have to start on a UTC aligned grid. The still-forming bars are never stored and
+// are fetched again on every call. It is safe for concurrent use within a process.
+type PriceCache struct {
+	prices *prices
+	dir    string
+
+	mu sync.Mutex
+}
+
+// NewPriceCache creates a price cache in the directory, the directory is created on the first write.
+func NewPriceCache(client *Client, dir string) *PriceCache {
+	return &PriceCache{
+		prices: client.Prices(),
+		dir:    dir,
+	}
+}
+
+// History retrieves historical prices for the time range like Prices.HistoryRange, taking the bars already fetched
+// from the cache and fetching the rest from the API.
+func (c *PriceCache) History(
+	ctx context.Context,
+	epic string,
+	resolution Resolution,
+	from time.Time,
+	to time.Time,
+) (*Prices, error) {
+	barDuration := resolution.Duration()
+	if barDuration == 0 {
+		return nil, ErrUnknownResolution
+	}
+
+	if !from.Before(to) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	from, to = from.UTC(), to.UTC()
+
+	// the cache works with bar aligned half-open ranges
+	start := from.Truncate(barDuration)
+	end := to.Truncate(barDuration).Add(barDuration)
+	now := time.Now().UTC()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	buckets, err := c.loadBuckets(epic, resolution, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var covered []timeWindow
+	for _, b := range buckets {
+		covered = append(covered, b.covered...)
+	}
+
+	for _, gap := range missingWindows(start, end, covered) {
+		chunk, err := c.prices.HistoryRange(ctx, epic, resolution, gap.from, gap.to)
+		if err != nil {
+			return nil, err
+		}
+
+		// the range is covered up to the earliest forming bar, or the earliest time a forming bar can start without one
+		fetched := timeWindow{from: gap.from, to: gap.to}
+		formingFound := false
+
+		for _, price := range chunk.Prices {
+			if price.SnapshotTimeUTC.Before(gap.from) || !price.SnapshotTimeUTC.Before(gap.to) {
+				continue
+			}
+
+			if isFormingBar(price, barDuration, now) && !formingFound {
+				fetched.to = price.SnapshotTimeUTC
+				formingFound = true
+			}
+
+			if b := findBucket(buckets, price.SnapshotTimeUTC); b != nil {
+				b.addPrice(price, chunk.InstrumentType)
+			}
+		}
+
+		if !formingFound {
+			fetched.to = minTime(gap.to, now.Add(-barDuration))
+		}
+
+		for _, b := range buckets {
+			b.cover(fetched)
+		}
+	}
+
+	result := &Prices{}
+
+	for _, b := range buckets {
+		if err := c.saveBucket(b, barDuration, now); err != nil {
+			return nil, err
+		}
+
+		if result.InstrumentType == "" {
+			result.InstrumentType = b.instrumentType
+		}
+
+		for _, price := range b.prices {
+			if !price.SnapshotTimeUTC.Before(from) && !price.SnapshotTimeUTC.After(to) {
+				result.Prices = append(result.Prices, price)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Invalidate removes the cached prices of the epic and resolution for the days, or years, overlapping the time range.
+func (c *PriceCache) Invalidate(epic string, resolution Resolution, from, to time.Time) error {
+	if resolution.Duration() == 0 {
+		return ErrUnknownResolution
+	}
+
+	if to.Before(from) {
+		return ErrInvalidTimeRange
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for bucketStart := priceCacheBucketStart(resolution, from.UTC()); !bucketStart.After(to.UTC()); {
+		path := c.bucketPath(epic, resolution, bucketStart)
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return NewPriceCacheError(path, err)
+		}
+
+		bucketStart = priceCacheBucketEnd(resolution, bucketStart)
+	}
+
+	return nil
+}
+
+// priceCacheFile is the content of a cache file.
+type priceCacheFile struct {
+	InstrumentType string             `json:"instrumentType"`
+	Covered        []priceCacheWindow `json:"covered"`
+	Prices         []Price            `json:"prices"`
+}
+
+type priceCacheWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// priceCacheBucket is a cache file loaded to memory, the covered windows are half-open and clipped to the bucket.
+type priceCacheBucket struct {
+	path  string
+	start time.Time
+	end   time.Time
+
+	instrumentType string
+	covered        []timeWindow
+	prices         []Price
+
+	dirty bool
+}
+
+func (b *priceCacheBucket) addPrice(price Price, instrumentType string) {
+	if b.instrumentType == "" {
+		b.instrumentType = instrumentType
+	}
+
+	i, found := slices.BinarySearchFunc(b.prices, price.SnapshotTimeUTC, func(p Price, t time.Time) int {
+		return p.SnapshotTimeUTC.Compare(t)
+	})
+
+	if found {
+		b.prices[i] = price
+	} else {
+		b.prices = slices.Insert(b.prices, i, price)
+	}
+
+	b.dirty = true
+}
+
+func (b *priceCacheBucket) cover(window timeWindow) {
+	window.from = maxTime(window.from, b.start)
+	window.to = minTime(window.to, b.end)
+
+	if !window.from.Before(window.to) {
+		return
+	}
+
+	b.covered = mergeWindows(append(b.covered, window))
+	b.dirty = true
+}
+
+func (c *PriceCache) loadBuckets(
+	epic string,
+	resolution Resolution,
+	start time.Time,
+	end time.Time,
+) ([]*priceCacheBucket, error) {
+	var buckets []*priceCacheBucket
+
+	for bucketStart := priceCacheBucketStart(resolution, start); bucketStart.Before(end); {
+		b := &priceCacheBucket{
+			path:  c.bucketPath(epic, resolution, bucketStart),
+			start: bucketStart,
+			end:   priceCacheBucketEnd(resolution, bucketStart),
+		}
+
+		if err := b.load(); err != nil {
+			return nil, err
+		}
+
+		buckets = append(buckets, b)
+		bucketStart = b.end
+	}
+
+	return buckets, nil
+}
+
+func (b *priceCacheBucket) load() error {
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	file := priceCacheFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	b.instrumentType = file.InstrumentType
+	b.prices = file.Prices
+
+	for _, w := range file.Covered {
+		b.covered = append(b.covered, timeWindow{from: w.From.UTC(), to: w.To.UTC()})
+	}
+
+	slices.SortStableFunc(b.prices, func(a, b Price) int {
+		return a.SnapshotTimeUTC.Compare(b.SnapshotTimeUTC)
+	})
+
+	return nil
+}
+
+// saveBucket writes the bucket if it has changed, leaving out the still-forming bars.
+// The file is replaced atomically, so a failed write doesn't corrupt the cache.
+func (c *PriceCache) saveBucket(b *priceCacheBucket, barDuration time.Duration, now time.Time) error {
+	if !b.dirty {
+		return nil
+	}
+
+	file := priceCacheFile{
+		InstrumentType: b.instrumentType,
+		Covered:        make([]priceCacheWindow, 0, len(b.covered)),
+		Prices:         make([]Price, 0, len(b.prices)),
+	}
+
+	for _, w := range b.covered {
+		file.Covered = append(file.Covered, priceCacheWindow{From: w.from, To: w.to})
+	}
+
+	for _, price := range b.prices {
+		if !isFormingBar(price, barDuration, now) {
+			file.Prices = append(file.Prices, price)
+		}
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), priceCacheDirPerm); err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(b.path), filepath.Base(b.path)+".*.tmp")
+	if err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return NewPriceCacheError(b.path, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	if err := os.Chmod(tmp.Name(), priceCacheFilePerm); err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), b.path); err != nil {
+		return NewPriceCacheError(b.path, err)
+	}
+
+	b.dirty = false
+
+	return nil
+}
+
+func (c *PriceCache) bucketPath(epic string, resolution Resolution, bucketStart time.Time) string {
+	name := bucketStart.Format(time.DateOnly)
+	if isLongResolution(resolution) {
+		name = bucketStart.Format("2006")
+	}
+
+	return filepath.Join(c.dir, url.PathEscape(epic), string(resolution), name+".json")
+}
+
+// isFormingBar reports whether the bar hasn't been completed yet at the time.
+func isFormingBar(price Price, barDuration time.Duration, now time.Time) bool {
+	return price.SnapshotTimeUTC.Add(barDuration).After(now)
+}
+
+func findBucket(buckets []*priceCacheBucket, t time.Time) *priceCacheBucket {
+	for _, b := range buckets {
+		if !t.Before(b.start) && t.Before(b.end) {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// priceCacheBucketStart returns the beginning of the UTC day, or the year for the DAY and WEEK resolutions.
+func priceCacheBucketStart(resolution Resolution, t time.Time) time.Time {
+	if isLongResolution(resolution) {
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func priceCacheBucketEnd(resolution Resolution, bucketStart time.Time) time.Time {
+	if isLongResolution(resolution) {
+		return bucketStart.AddDate(1, 0, 0)
+	}
+
+	return bucketStart.AddDate(0, 0, 1)
+}
+
+func isLongResolution(resolution Resolution) bool {
+	return resolution == ResolutionDay || resolution == ResolutionWeek
+}
+
+// mergeWindows sorts half-open windows and merges the overlapping and adjacent ones.
+func mergeWindows(windows []timeWindow) []timeWindow {
+	slices.SortFunc(windows, func(a, b timeWindow) int {
+		return a.from.Compare(b.from)
+	})
+
+	merged := windows[:0]
+
+	for _, w := range windows {
+		if n := len(merged); n > 0 && !w.from.After(merged[n-1].to) {
+			merged[n-1].to = maxTime(merged[n-1].to, w.to)
+
+			continue
+		}
+
+		merged = append(merged, w)
+	}
+
+	return merged
+}
+
+// missingWindows returns the parts of the half-open range [start, end) not covered by the windows.
+func missingWindows(start, end time.Time, covered []timeWindow) []timeWindow {
+	var missing []timeWindow
+
+	cursor := start
+
+	for _, w := range mergeWindows(covered) {
+		if !cursor.Before(end) {
+			break
+		}
+
+		if w.from.After(cursor) {
+			missing = append(missing, timeWindow{from: cursor, to: minTime(w.from, end)})
+		}
+
+		cursor = maxTime(cursor, w.to)
+	}
+
+	if cursor.Before(end) {
+		missing = append(missing, timeWindow{from: cursor, to: end})
+	}
+
+	return missing
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,294 @@
+package capitalcom_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceCache_HistoryFetchesOnlyMissingBars(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 23, 0, 0, 0, time.UTC)
+	srv, requests := newMinutePricesServer(t, from.Add(30*time.Minute), from.Add(40*time.Minute))
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	dir := t.TempDir()
+	underTest := capitalcom.NewPriceCache(client, dir)
+
+	first, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute,
+		from, from.Add(20*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, first.Prices, 21)
+
+	// Act
+	cached, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute,
+		from.Add(5*time.Minute), from.Add(15*time.Minute))
+	require.NoError(t, err)
+
+	extended, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute,
+		from.Add(10*time.Minute), from.Add(90*time.Minute))
+	require.NoError(t, err)
+
+	// Assert
+	require.Equal(t, first.Prices[5:16], cached.Prices)
+	require.Equal(t, "CRYPTOCURRENCIES", cached.InstrumentType)
+
+	require.Len(t, extended.Prices, 81)
+	require.Equal(t, from.Add(10*time.Minute), extended.Prices[0].SnapshotTimeUTC)
+	require.Equal(t, from.Add(90*time.Minute), extended.Prices[80].SnapshotTimeUTC)
+
+	require.Equal(t, []pricesRequest{
+		{from: from, to: from.Add(21 * time.Minute), max: "1000"},
+		{from: from.Add(21 * time.Minute), to: from.Add(91 * time.Minute), max: "1000"},
+	}, requests())
+
+	require.FileExists(t, filepath.Join(dir, "BTCUSD", "MINUTE", "2026-06-22.json"))
+	require.FileExists(t, filepath.Join(dir, "BTCUSD", "MINUTE", "2026-06-23.json"))
+}
+
+func TestPriceCache_HistoryRemembersRangesWithoutPrices(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 20, 0, 0, 0, 0, time.UTC)
+	srv, requests := newMinutePricesServer(t, from, from.Add(time.Hour))
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	underTest := capitalcom.NewPriceCache(client, t.TempDir())
+
+	_, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute,
+		from, from.Add(30*time.Minute))
+	require.NoError(t, err)
+
+	// Act
+	actual, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute,
+		from, from.Add(30*time.Minute))
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, actual.Prices)
+	require.Len(t, requests(), 1)
+}
+
+func TestPriceCache_HistoryDoesNotCoverFailedFetches(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	pricesSrv, requests := newMinutePricesServer(t, time.Time{}, time.Time{})
+
+	var calls atomic.Int32
+
+	// the first request fails as if the epic is unknown, the next ones are served
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorCode":"error.not-found.epic"}`))
+
+			return
+		}
+
+		pricesSrv.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	dir := t.TempDir()
+	underTest := capitalcom.NewPriceCache(client, dir)
+
+	_, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute,
+		from, from.Add(30*time.Minute))
+	require.ErrorIs(t, err, capitalcom.ErrorCodeNotFoundEpic)
+	require.NoFileExists(t, filepath.Join(dir, "BTCUSD", "MINUTE", "2026-06-22.json"))
+
+	// Act
+	actual, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute,
+		from, from.Add(30*time.Minute))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, actual.Prices, 31)
+	require.Len(t, requests(), 1)
+}
+
+func TestPriceCache_HistoryRefetchesFormingBar(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	to := time.Now().UTC()
+	from := to.Add(-30 * time.Minute)
+	srv, requests := newMinutePricesServer(t, time.Time{}, time.Time{})
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	underTest := capitalcom.NewPriceCache(client, t.TempDir())
+
+	_, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, to)
+	require.NoError(t, err)
+
+	// Act
+	actual, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, to)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, to.Truncate(time.Minute), actual.Prices[len(actual.Prices)-1].SnapshotTimeUTC)
+	require.Len(t, requests(), 2)
+	require.WithinDuration(t, to, requests()[1].from, 2*time.Minute)
+}
+
+func TestPriceCache_HistoryRefetchesFormingBarOffTheGrid(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	now := time.Now().UTC()
+
+	// daily bars open off the UTC grid, so the forming one started the previous UTC day and ends within a minute
+	formingBarStart := now.Truncate(time.Minute).Add(-24*time.Hour + time.Minute)
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// every response has a new close price of the forming bar
+		closePrice := float64(calls.Add(1))
+
+		to, err := time.Parse(pricesDateFormat, r.URL.Query().Get("to"))
+		require.NoError(t, err)
+
+		bars := make([]map[string]any, 0)
+
+		last := formingBarStart
+		if to.Before(last) {
+			last = to
+		}
+
+		for snapshot := formingBarStart.Add(-5 * 24 * time.Hour); !snapshot.After(last); snapshot = snapshot.Add(24 * time.Hour) {
+			bars = append(bars, map[string]any{
+				"snapshotTime":     snapshot.Format(pricesDateFormat),
+				"snapshotTimeUTC":  snapshot.Format(pricesDateFormat),
+				"openPrice":        map[string]float64{"bid": 1, "ask": 1},
+				"closePrice":       map[string]float64{"bid": closePrice, "ask": closePrice},
+				"highPrice":        map[string]float64{"bid": 1, "ask": 1},
+				"lowPrice":         map[string]float64{"bid": 1, "ask": 1},
+				"lastTradedVolume": 1,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"prices": bars, "instrumentType": "CRYPTOCURRENCIES"})
+	}))
+	t.Cleanup(srv.Close)
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	underTest := capitalcom.NewPriceCache(client, t.TempDir())
+
+	from := formingBarStart.Add(-3 * 24 * time.Hour)
+
+	first, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionDay, from, now)
+	require.NoError(t, err)
+	require.Equal(t, capitalcom.PriceData{Bid: 1, Ask: 1}, first.Prices[len(first.Prices)-1].ClosePrice)
+
+	// Act
+	actual, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionDay, from, now)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, actual.Prices, 4)
+	require.Equal(t, formingBarStart, actual.Prices[3].SnapshotTimeUTC)
+	require.Equal(t, capitalcom.PriceData{Bid: 2, Ask: 2}, actual.Prices[3].ClosePrice)
+	require.Equal(t, capitalcom.PriceData{Bid: 1, Ask: 1}, actual.Prices[2].ClosePrice)
+}
+
+func TestPriceCache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 10, 0, 0, 0, time.UTC)
+	srv, requests := newMinutePricesServer(t, time.Time{}, time.Time{})
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	dir := t.TempDir()
+	underTest := capitalcom.NewPriceCache(client, dir)
+
+	_, err := underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, from.Add(time.Hour))
+	require.NoError(t, err)
+
+	// Act
+	err = underTest.Invalidate("BTCUSD", capitalcom.ResolutionMinute, from, from)
+
+	// Assert
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "BTCUSD", "MINUTE", "2026-06-22.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = underTest.History(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, from.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, requests(), 2)
+}
+
+func TestPrice_MarshalJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	expected := capitalcom.Price{
+		SnapshotTime:     time.Date(2026, 6, 22, 12, 0, 0, 0, time.UTC),
+		SnapshotTimeUTC:  time.Date(2026, 6, 22, 10, 0, 0, 0, time.UTC),
+		OpenPrice:        capitalcom.PriceData{Bid: 1, Ask: 2},
+		ClosePrice:       capitalcom.PriceData{Bid: 3, Ask: 4},
+		HighPrice:        capitalcom.PriceData{Bid: 5, Ask: 6},
+		LowPrice:         capitalcom.PriceData{Bid: 0.5, Ask: 1.5},
+		LastTradedVolume: 42,
+	}
+
+	data, err := expected.MarshalJSON()
+	require.NoError(t, err)
+
+	actual := capitalcom.Price{}
+
+	// Act
+	err = actual.UnmarshalJSON(data)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
@@ -0,0 +1,121 @@
+package capitalcom
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// MaxPricesPerRequest is the maximum number of bars the API returns for a single request.
+const MaxPricesPerRequest = 1000
+
+// HistoryRange retrieves historical prices for the whole time range, which can exceed the limit of a single
+// request. The range is split into chunks of MaxPricesPerRequest bars fetched one by one, so the client's
+// rate limits are respected, see WithRateLimits. Bars are de-duplicated by SnapshotTimeUTC and ordered by it.
+// Chunks without prices, e.g. weekends, are skipped.
+func (p *prices) HistoryRange(
+	ctx context.Context,
+	epic string,
+	resolution Resolution,
+	from time.Time,
+	to time.Time,
+) (*Prices, error) {
+	windows, err := historyWindows(resolution, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Prices{}
+	seen := make(map[time.Time]struct{})
+
+	for _, window := range windows {
+		chunk, err := p.historyWindow(ctx, epic, resolution, window)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.InstrumentType == "" {
+			result.InstrumentType = chunk.InstrumentType
+		}
+
+		for _, price := range chunk.Prices {
+			if _, ok := seen[price.SnapshotTimeUTC]; ok {
+				continue
+			}
+
+			seen[price.SnapshotTimeUTC] = struct{}{}
+
+			result.Prices = append(result.Prices, price)
+		}
+	}
+
+	slices.SortStableFunc(result.Prices, func(a, b Price) int {
+		return a.SnapshotTimeUTC.Compare(b.SnapshotTimeUTC)
+	})
+
+	return result, nil
+}
+
+// historyWindow retrieves prices of the time window, a window without prices results in empty prices.
+func (p *prices) historyWindow(
+	ctx context.Context,
+	epic string,
+	resolution Resolution,
+	window timeWindow,
+) (*Prices, error) {
+	chunk, err := p.History(ctx, epic, PricesParams{
+		Resolution: resolution,
+		Max:        MaxPricesPerRequest,
+		From:       window.from,
+		To:         window.to,
+	})
+
+	if isPricesNotFound(err) {
+		return &Prices{}, nil
+	}
+
+	return chunk, err
+}
+
+// isPricesNotFound reports whether the API has no prices for the window. Other not found errors,
+// e.g. an unknown epic, are returned as is.
+func isPricesNotFound(err error) bool {
+	return errors.Is(err, ErrorCodePricesNotFound)
+}
+
+type timeWindow struct {
+	from time.Time
+	to   time.Time
+}
+
+// historyWindows splits the time range into windows of MaxPricesPerRequest bars of the resolution.
+func historyWindows(resolution Resolution, from, to time.Time) ([]timeWindow, error) {
+	barDuration := resolution.Duration()
+	if barDuration == 0 {
+		return nil, ErrUnknownResolution
+	}
+
+	if !from.Before(to) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	// the bounds are inclusive, so the window covers one bar less than the maximum
+	windowDuration := barDuration * (MaxPricesPerRequest - 1)
+
+	var windows []timeWindow
+
+	for start := from; start.Before(to); start = start.Add(windowDuration) {
+		windows = append(windows, timeWindow{from: start, to: minTime(start.Add(windowDuration), to)})
+	}
+
+	return windows, nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
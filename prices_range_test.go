@@ -0,0 +1,171 @@
+package capitalcom_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+const pricesDateFormat = "2006-01-02T15:04:05"
+
+type pricesRequest struct {
+	from time.Time
+	to   time.Time
+	max  string
+}
+
+// newMinutePricesServer serves minute bars for every requested range except the ranges starting in the gap.
+func newMinutePricesServer(t *testing.T, gapFrom, gapTo time.Time) (*httptest.Server, func() []pricesRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []pricesRequest
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		from, err := time.Parse(pricesDateFormat, query.Get("from"))
+		require.NoError(t, err)
+
+		to, err := time.Parse(pricesDateFormat, query.Get("to"))
+		require.NoError(t, err)
+
+		mu.Lock()
+		requests = append(requests, pricesRequest{from: from, to: to, max: query.Get("max")})
+		mu.Unlock()
+
+		if !from.Before(gapFrom) && from.Before(gapTo) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorCode":"error.prices.not-found"}`))
+
+			return
+		}
+
+		bars := make([]map[string]any, 0)
+
+		for snapshot := from; !snapshot.After(to); snapshot = snapshot.Add(time.Minute) {
+			bars = append(bars, map[string]any{
+				"snapshotTime":     snapshot.Format(pricesDateFormat),
+				"snapshotTimeUTC":  snapshot.Format(pricesDateFormat),
+				"openPrice":        map[string]float64{"bid": 1, "ask": 2},
+				"closePrice":       map[string]float64{"bid": 1, "ask": 2},
+				"highPrice":        map[string]float64{"bid": 1, "ask": 2},
+				"lowPrice":         map[string]float64{"bid": 1, "ask": 2},
+				"lastTradedVolume": 1,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"prices": bars, "instrumentType": "CRYPTOCURRENCIES"})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []pricesRequest {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+}
+
+func TestPrices_HistoryRange(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2500 * time.Minute)
+	srv, requests := newMinutePricesServer(t, time.Time{}, time.Time{})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	// Act
+	actual, err := underTest.Prices().HistoryRange(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, to)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "CRYPTOCURRENCIES", actual.InstrumentType)
+	require.Len(t, actual.Prices, 2501)
+
+	for i, price := range actual.Prices {
+		require.Equal(t, from.Add(time.Duration(i)*time.Minute), price.SnapshotTimeUTC)
+	}
+
+	require.Len(t, requests(), 3)
+
+	for _, request := range requests() {
+		require.Equal(t, "1000", request.max)
+		require.LessOrEqual(t, request.to.Sub(request.from), 999*time.Minute)
+	}
+}
+
+func TestPrices_HistoryRangeSkipsChunksWithoutPrices(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2500 * time.Minute)
+	srv, _ := newMinutePricesServer(t, from.Add(999*time.Minute), from.Add(1998*time.Minute))
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	// Act
+	actual, err := underTest.Prices().HistoryRange(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, to)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, actual.Prices, 1000+503)
+	require.Equal(t, from.Add(999*time.Minute), actual.Prices[999].SnapshotTimeUTC)
+	require.Equal(t, from.Add(1998*time.Minute), actual.Prices[1000].SnapshotTimeUTC)
+}
+
+func TestPrices_HistoryRangeReturnsErrorForInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewClient(expectedAPIKey, identifier, password)
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	_, rangeErr := underTest.Prices().HistoryRange(context.Background(), "BTCUSD", capitalcom.ResolutionMinute, from, from)
+	_, resolutionErr := underTest.Prices().HistoryRange(context.Background(), "BTCUSD", "MONTH", from, from.Add(time.Hour))
+
+	// Assert
+	require.ErrorIs(t, rangeErr, capitalcom.ErrInvalidTimeRange)
+	require.ErrorIs(t, resolutionErr, capitalcom.ErrUnknownResolution)
+}
+
+func TestPrices_HistoryRangeReturnsErrorForUnknownEpic(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := newErrorResponseClient(t, http.StatusNotFound, `{"errorCode":"error.not-found.epic"}`)
+	from := time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	actual, err := underTest.Prices().HistoryRange(context.Background(),
+		"UNKNOWN",
+		capitalcom.ResolutionMinute,
+		from,
+		from.Add(time.Hour))
+
+	// Assert
+	require.ErrorIs(t, err, capitalcom.ErrorCodeNotFoundEpic)
+	require.Nil(t, actual)
+}
@@ -0,0 +1,187 @@
+package capitalcom
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit allows the number of Requests per Interval, e.g. 10 requests per second.
+// A zero value means no limit.
+type RateLimit struct {
+	Requests int
+	Interval time.Duration
+}
+
+// RateLimits defines request limits per endpoint class.
+// A request waits until it is allowed by all limits of its classes.
+type RateLimits struct {
+	// General applies to every request, including generic GETs.
+	General RateLimit
+	// Session applies to the session creation.
+	Session RateLimit
+	// Positions applies to requests opening, updating and closing positions.
+	Positions RateLimit
+	// WorkingOrders applies to requests creating, updating and deleting working orders.
+	WorkingOrders RateLimit
+	// Trading applies to both positions and working orders changing requests.
+	Trading RateLimit
+}
+
+// DefaultRateLimits returns the limits documented by Capital.com: 10 requests per second per user,
+// 1 request per second for the session creation and 1 request per 0.1 seconds for trading requests.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		General:       RateLimit{Requests: 10, Interval: time.Second},           //nolint:mnd
+		Session:       RateLimit{Requests: 1, Interval: time.Second},            //nolint:mnd
+		Positions:     RateLimit{Requests: 1, Interval: 100 * time.Millisecond}, //nolint:mnd
+		WorkingOrders: RateLimit{Requests: 1, Interval: 100 * time.Millisecond}, //nolint:mnd
+	}
+}
+
+// DemoRateLimits returns DefaultRateLimits with the additional limit of 1000 trading requests per hour
+// applied to demo accounts.
+func DemoRateLimits() RateLimits {
+	limits := DefaultRateLimits()
+	limits.Trading = RateLimit{Requests: 1000, Interval: time.Hour} //nolint:mnd
+
+	return limits
+}
+
+// WithRateLimits enables client-side rate limiting, requests exceeding the limits wait for their turn.
+func WithRateLimits(limits RateLimits) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = newRateLimiter(limits)
+	}
+}
+
+type rateLimiter struct {
+	general       *tokenBucket
+	session       *tokenBucket
+	positions     *tokenBucket
+	workingOrders *tokenBucket
+	trading       *tokenBucket
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	return &rateLimiter{
+		general:       newTokenBucket(limits.General),
+		session:       newTokenBucket(limits.Session),
+		positions:     newTokenBucket(limits.Positions),
+		workingOrders: newTokenBucket(limits.WorkingOrders),
+		trading:       newTokenBucket(limits.Trading),
+	}
+}
+
+// wait blocks until the request is allowed by all limits of its classes or the context is done.
+func (l *rateLimiter) wait(ctx context.Context, method, resourcePath string) error {
+	buckets := l.buckets(method, resourcePath)
+
+	var delay time.Duration
+
+	for _, b := range buckets {
+		delay = max(delay, b.reserve())
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		for _, b := range buckets {
+			b.cancel()
+		}
+
+		return NewRateLimitWaitError(ctx.Err())
+	}
+}
+
+func (l *rateLimiter) buckets(method, resourcePath string) []*tokenBucket {
+	path, _, _ := strings.Cut(resourcePath, "?")
+
+	buckets := []*tokenBucket{l.general}
+
+	switch {
+	case method == http.MethodPost && path == "/session":
+		buckets = append(buckets, l.session)
+	case method != http.MethodGet && isResourcePath(path, "/positions"):
+		buckets = append(buckets, l.positions, l.trading)
+	case method != http.MethodGet && isResourcePath(path, "/workingorders"):
+		buckets = append(buckets, l.workingOrders, l.trading)
+	}
+
+	return buckets
+}
+
+func isResourcePath(path, resource string) bool {
+	return path == resource || strings.HasPrefix(path, resource+"/")
+}
+
+// tokenBucket is a token bucket refilled at the rate of the limit and holding up to the limit's Requests tokens.
+// A nil bucket doesn't limit anything.
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	// tokens may go below zero, then it is a number of reservations waiting for a refill
+	tokens   float64
+	interval time.Duration
+	last     time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Requests <= 0 || limit.Interval <= 0 {
+		return nil
+	}
+
+	return &tokenBucket{
+		capacity: float64(limit.Requests),
+		tokens:   float64(limit.Requests),
+		interval: limit.Interval,
+	}
+}
+
+// reserve takes a token and returns how long to wait until the token is available.
+func (b *tokenBucket) reserve() time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+
+	if !b.last.IsZero() {
+		refill := float64(now.Sub(b.last)) / float64(b.interval) * b.capacity
+		b.tokens = min(b.capacity, b.tokens+refill)
+	}
+
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.capacity * float64(b.interval))
+}
+
+// cancel returns a reserved token.
+func (b *tokenBucket) cancel() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.capacity, b.tokens+1)
+}
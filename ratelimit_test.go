@@ -0,0 +1,130 @@
+package capitalcom_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			handleSessionCreation(w, r)
+		case capitalcom.APIPathV1 + "/positions":
+			if !isAuthorized(w, r) {
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"dealReference":"o_12345"}`))
+		case capitalcom.APIPathV1 + "/ping":
+			if !isAuthorized(w, r) {
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"status":"OK"}`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	return srv
+}
+
+func TestClient_WithRateLimitsDelaysRequestsOverLimit(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	srv := newRateLimitedServer(t)
+
+	const interval = 50 * time.Millisecond
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithRateLimits(capitalcom.RateLimits{
+			Positions: capitalcom.RateLimit{Requests: 1, Interval: interval},
+		}))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	start := time.Now()
+
+	// Act
+	for range 3 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := underTest.Positions().Open(ctx, capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Epic:      "BTCUSD",
+				Size:      1,
+			})
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	elapsed := time.Since(start)
+
+	// pings aren't limited by the positions limit
+	pingStart := time.Now()
+	_, err = underTest.Ping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, elapsed, 2*interval)
+	require.Less(t, time.Since(pingStart), interval)
+}
+
+func TestClient_WithRateLimitsReturnsErrorWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv := newRateLimitedServer(t)
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithRateLimits(capitalcom.RateLimits{
+			Session: capitalcom.RateLimit{Requests: 1, Interval: time.Hour},
+		}))
+
+	_, err := underTest.Session().CreateNew(context.Background(), false)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	t.Cleanup(cancel)
+
+	var waitErr capitalcom.RateLimitWaitError
+
+	// Act
+	_, err = underTest.Session().CreateNew(ctx, false)
+
+	// Assert
+	require.ErrorAs(t, err, &waitErr)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,146 @@
+package capitalcom
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedFields are headers and JSON fields masked in logged request and response dumps.
+var defaultRedactedFields = []string{ //nolint:gochecknoglobals
+	HeaderAPIKey,
+	HeaderKeySecurityToken,
+	HeaderTokenCST,
+	"password",
+	"identifier",
+}
+
+// WithRedactedFields adds headers and JSON fields to be masked in logged request and response dumps
+// in addition to the API key, the session tokens, the identifier and the password, which are always masked.
+// Header names are matched case-insensitively, JSON fields are matched by the exact name at any depth.
+// The whole value of a field is masked, including the nested objects and arrays.
+func WithRedactedFields(fields ...string) ClientOption {
+	return func(c *Client) {
+		c.redactedFields = append(c.redactedFields, fields...)
+	}
+}
+
+// redactor masks sensitive headers and JSON fields in HTTP dumps.
+type redactor struct {
+	headers map[string]struct{}
+	fields  *regexp.Regexp
+}
+
+func newRedactor(extraFields []string) *redactor {
+	fields := slices.Concat(defaultRedactedFields, extraFields)
+
+	headers := make(map[string]struct{}, len(fields))
+	quoted := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		headers[strings.ToLower(field)] = struct{}{}
+		quoted = append(quoted, regexp.QuoteMeta(field))
+	}
+
+	return &redactor{
+		headers: headers,
+		// matches a field name up to the beginning of its value
+		fields: regexp.MustCompile(`"(?:` + strings.Join(quoted, "|") + `)"\s*:\s*`),
+	}
+}
+
+// redact returns a copy of the dump with the values of the sensitive headers and JSON fields masked.
+func (r *redactor) redact(dump []byte) []byte {
+	head, body, hasBody := bytes.Cut(dump, []byte("\r\n\r\n"))
+	lines := bytes.Split(head, []byte("\r\n"))
+
+	// the first line is a request or status line
+	for i := 1; i < len(lines); i++ {
+		name, _, ok := bytes.Cut(lines[i], []byte(":"))
+		if !ok {
+			continue
+		}
+
+		if _, ok := r.headers[strings.ToLower(string(bytes.TrimSpace(name)))]; ok {
+			lines[i] = slices.Concat(name, []byte(": "+redactedValue))
+		}
+	}
+
+	redacted := bytes.Join(lines, []byte("\r\n"))
+
+	if !hasBody {
+		return redacted
+	}
+
+	redacted = append(redacted, "\r\n\r\n"...)
+
+	return append(redacted, r.redactFields(body)...)
+}
+
+// redactFields masks the values of the sensitive JSON fields, the fields nested in a masked value are skipped.
+func (r *redactor) redactFields(body []byte) []byte {
+	var (
+		redacted []byte
+		cursor   int
+	)
+
+	for _, match := range r.fields.FindAllIndex(body, -1) {
+		if match[0] < cursor {
+			continue
+		}
+
+		end := jsonValueEnd(body, match[1])
+
+		redacted = append(redacted, body[cursor:match[1]]...)
+		redacted = append(redacted, `"`+redactedValue+`"`...)
+		cursor = end
+	}
+
+	return append(redacted, body[cursor:]...)
+}
+
+// jsonValueEnd returns the end of the JSON value starting at the offset. The value may be truncated,
+// as dumps can be, then the end of the data is returned.
+func jsonValueEnd(data []byte, offset int) int {
+	depth := 0
+	inString := false
+
+	for i := offset; i < len(data); i++ {
+		c := data[i]
+
+		switch {
+		case inString:
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			if depth == 0 {
+				return i
+			}
+
+			depth--
+
+			if depth == 0 {
+				return i + 1
+			}
+		case depth == 0 && (c == ',' || c == ' ' || c == '\t' || c == '\r' || c == '\n'):
+			return i
+		}
+	}
+
+	return len(data)
+}
@@ -0,0 +1,86 @@
+package capitalcom_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_LogsRedactedDumps(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(handleSessionCreation))
+	t.Cleanup(srv.Close)
+
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithLogger(logger),
+		capitalcom.WithRedactedFields("clientId"))
+
+	// Act
+	_, err := underTest.Session().CreateNew(context.Background(), false)
+
+	// Assert
+	require.NoError(t, err)
+
+	output := logs.String()
+
+	require.Contains(t, output, "sending a request")
+	require.Contains(t, output, "received a response")
+	require.Contains(t, output, `X-Cap-Api-Key: [REDACTED]`)
+	require.Contains(t, output, `X-Security-Token: [REDACTED]`)
+	require.Contains(t, output, `Cst: [REDACTED]`)
+	require.Contains(t, output, `\"password\":\"[REDACTED]\"`)
+	require.Contains(t, output, `\"identifier\":\"[REDACTED]\"`)
+	require.Contains(t, output, `\"clientId\": \"[REDACTED]\"`)
+	require.NotContains(t, output, expectedAPIKey)
+	require.NotContains(t, output, expectedKeySecurityToken)
+	require.NotContains(t, output, identifier)
+	require.NotContains(t, output, `:\"`+password+`\"`)
+}
+
+func TestClient_LogsRedactedNestedValues(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(handleSessionCreation))
+	t.Cleanup(srv.Close)
+
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithLogger(logger),
+		capitalcom.WithRedactedFields("accountInfo", "accounts"))
+
+	// Act
+	_, err := underTest.Session().CreateNew(context.Background(), false)
+
+	// Assert
+	require.NoError(t, err)
+
+	output := logs.String()
+
+	require.Contains(t, output, `\"accountInfo\": \"[REDACTED]\",`)
+	require.Contains(t, output, `\"accounts\": \"[REDACTED]\"`)
+	require.Contains(t, output, `\"currencyIsoCode\": \"USD\"`)
+	require.NotContains(t, output, "92.89")
+	require.NotContains(t, output, "12345678907654321")
+}
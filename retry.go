@@ -0,0 +1,154 @@
+package capitalcom
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinBackoff  = 500 * time.Millisecond
+	defaultRetryMaxBackoff  = 10 * time.Second
+)
+
+// RetryPolicy defines how requests failed with transient errors are retried.
+//
+// GET requests are retried on network errors, including timeouts of the HTTP client, 429 Too Many Requests
+// and 5xx responses. Requests are never retried once the context of the request is done.
+// Other requests, e.g. opening positions or creating working orders, are not idempotent
+// and might have been processed by the API even if the response has been lost,
+// so they are retried on 429 Too Many Requests only, which guarantees the request has been rejected.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, it doubles after every attempt up to MaxBackoff.
+	// A random jitter is added to the delay. The Retry-After header takes precedence if present,
+	// but it is limited by MaxBackoff as well.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts with the backoff starting from 0.5 seconds up to 10 seconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+	}
+}
+
+// WithRetryPolicy enables retrying requests failed with transient errors, see RetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// sendRequestWithRetry sends the request retrying transient failures according to the client's retry policy.
+func sendRequestWithRetry(
+	ctx context.Context,
+	c *Client,
+	method string,
+	resourcePath string,
+	reqBody []byte,
+	headers http.Header,
+) (*http.Response, error) {
+	policy := c.retryPolicy
+	if policy == nil {
+		return sendRequest(ctx, c, method, resourcePath, reqBody, headers)
+	}
+
+	delay := max(policy.MinBackoff, 0)
+
+	for attempt := 1; ; attempt++ {
+		res, err := sendRequest(ctx, c, method, resourcePath, reqBody, headers)
+
+		if attempt >= policy.MaxAttempts || !shouldRetry(ctx, method, res, err) {
+			return res, err
+		}
+
+		wait := withRetryJitter(delay)
+
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+				wait = min(retryAfter, policy.MaxBackoff)
+			}
+
+			closeResponseBody(res, c.logger)
+		}
+
+		c.logger.With("method", method, "path", resourcePath, "attempt", attempt, "delay", wait).
+			Warn("request failed with a transient error, retrying")
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, NewRetryWaitError(err)
+		}
+
+		delay = min(delay*2, policy.MaxBackoff) //nolint:mnd
+	}
+}
+
+func shouldRetry(ctx context.Context, method string, res *http.Response, err error) bool {
+	if err != nil {
+		return method == http.MethodGet && isTransientRequestError(err) && ctx.Err() == nil
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return method == http.MethodGet && isTransientStatus(res.StatusCode)
+}
+
+// isTransientRequestError reports whether the request has failed on the network level for a reason other than
+// cancellation. A timeout of the HTTP client is transient even though the error matches context.DeadlineExceeded,
+// so the callers check their context to tell it from the deadline of the request.
+// Rate limit waiting errors are not transient as they are caused by the context only.
+func isTransientRequestError(err error) bool {
+	var httpErr HTTPRequestError
+
+	return errors.As(err, &httpErr) && !errors.Is(err, context.Canceled)
+}
+
+// isTransientStatus reports whether a request responded with the status can succeed if sent again.
+func isTransientStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses the Retry-After header value which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+func withRetryJitter(delay time.Duration) time.Duration {
+	return delay/2 + time.Duration(rand.Int64N(int64(delay/2)+1)) //nolint:gosec,mnd
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,245 @@
+package capitalcom_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer responds to the path with the given statuses in order, then with 200 OK and the given body.
+func newFlakyServer(t *testing.T, path, body string, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	calls := &atomic.Int32{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != capitalcom.APIPathV1+path {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		call := int(calls.Add(1))
+
+		if call <= len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[call-1])
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	return srv, calls
+}
+
+func newRetryingClient(srv *httptest.Server) *capitalcom.Client {
+	return capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithRetryPolicy(capitalcom.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+		}))
+}
+
+func TestClient_WithRetryPolicyRetriesGetRequests(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv, calls := newFlakyServer(t, "/ping", `{"status":"OK"}`,
+		http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	underTest := newRetryingClient(srv)
+
+	// Act
+	status, err := underTest.Ping(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "OK", status)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_WithRetryPolicyReturnsErrorWhenAttemptsAreExhausted(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv, calls := newFlakyServer(t, "/ping", `{"status":"OK"}`,
+		http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	underTest := newRetryingClient(srv)
+
+	var apiErr capitalcom.APIError
+
+	// Act
+	_, err := underTest.Ping(context.Background())
+
+	// Assert
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadGateway, apiErr.StatusCode())
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_WithRetryPolicyDoesNotRetryNonIdempotentRequestsOnServerErrors(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv, calls := newFlakyServer(t, "/positions", `{"dealReference":"o_12345"}`, http.StatusInternalServerError)
+	underTest := newRetryingClient(srv)
+
+	var apiErr capitalcom.APIError
+
+	// Act
+	_, err := underTest.Positions().Open(context.Background(), capitalcom.OpenPositionRequest{
+		Direction: capitalcom.PositionDirectionBuy,
+		Epic:      "BTCUSD",
+		Size:      1,
+	})
+
+	// Assert
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusInternalServerError, apiErr.StatusCode())
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_WithRetryPolicyRetriesNonIdempotentRequestsOnTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	srv, calls := newFlakyServer(t, "/positions", `{"dealReference":"o_12345"}`, http.StatusTooManyRequests)
+	underTest := newRetryingClient(srv)
+
+	// Act
+	dealReference, err := underTest.Positions().Open(context.Background(), capitalcom.OpenPositionRequest{
+		Direction: capitalcom.PositionDirectionBuy,
+		Epic:      "BTCUSD",
+		Size:      1,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "o_12345", dealReference)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_WithRetryPolicyRetriesHTTPClientTimeouts(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// the first request outlives the timeout of the HTTP client
+		if calls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"OK"}`))
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	httpClient := srv.Client()
+	httpClient.Timeout = 50 * time.Millisecond
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(httpClient),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithRetryPolicy(capitalcom.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+		}))
+
+	// Act
+	status, err := underTest.Ping(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "OK", status)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_WithRetryPolicyDoesNotRetryWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := newRetryingClient(srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	t.Cleanup(cancel)
+
+	// Act
+	_, err := underTest.Ping(ctx)
+
+	// Assert
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_WithRetryPolicyLimitsRetryAfterByMaxBackoff(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"OK"}`))
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := newRetryingClient(srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	t.Cleanup(cancel)
+
+	// Act
+	status, err := underTest.Ping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "OK", status)
+	require.Equal(t, int32(2), calls.Load())
+}
@@ -106,6 +106,12 @@ func (s *session) CreateNew(
 	}
 
 	s.tokens.updateTokens(res.httpResponse)
+	s.tokens.setPasswordEncrypted(passwordIsEncrypted)
+	s.tokens.setActiveAccountID("")
+
+	if res.payload.StreamingHost != "" {
+		s.tokens.setStreamingHost(res.payload.StreamingHost)
+	}
 
 	return res.payload, nil
 }
@@ -135,6 +141,8 @@ func (s *session) Details(ctx context.Context) (*SessionData, error) {
 	return res.payload, nil
 }
 
+// SwitchActiveAccount switches the session to the account, the account is switched to again
+// whenever the session is renewed.
 func (s *session) SwitchActiveAccount(ctx context.Context, accountID string) (*AccountStatus, error) {
 	header := s.tokens.headers()
 
@@ -146,6 +154,7 @@ func (s *session) SwitchActiveAccount(ctx context.Context, accountID string) (*A
 	}
 
 	s.tokens.updateTokens(res.httpResponse)
+	s.tokens.setActiveAccountID(accountID)
 
 	return res.payload, nil
 }
@@ -162,3 +171,59 @@ func (s *session) LogOut(ctx context.Context) (string, error) {
 
 	return res.payload.Status, nil
 }
+
+// reauthenticationKey marks the context of the requests sent while renewing the session.
+type reauthenticationKey struct{}
+
+// canReauthenticate reports whether a request sent with the provided headers can be replayed with a renewed session.
+// Requests sent while renewing the session are never replayed.
+func (c *Client) canReauthenticate(ctx context.Context, headers http.Header) bool {
+	return c.autoReauthentication &&
+		headers.Get(HeaderTokenCST) != "" && //nolint:canonicalheader
+		ctx.Value(reauthenticationKey{}) == nil
+}
+
+// reauthenticate creates a new session the same way the current one has been created.
+// The expiredCST is the token that has been rejected, if the session has been already renewed
+// by a concurrent request in the meantime, a new session is not created.
+func (c *Client) reauthenticate(ctx context.Context, expiredCST string) error {
+	c.reauthenticationMu.Lock()
+	defer c.reauthenticationMu.Unlock()
+
+	if expiredCST != "" && c.tokens.currentCST() != expiredCST {
+		return nil
+	}
+
+	c.logger.Info("the session has expired, creating a new one")
+
+	ctx = context.WithValue(ctx, reauthenticationKey{}, true)
+	accountID := c.tokens.activeAccountID()
+	expiredCST, expiredSecurityToken := c.tokens.sessionTokens()
+
+	account, err := c.Session().CreateNew(ctx, c.tokens.isPasswordEncrypted())
+	if err != nil {
+		return NewSessionRenewalError(err)
+	}
+
+	if accountID == "" {
+		return nil
+	}
+
+	// CreateNew forgets the active account, it is remembered again for the next renewal
+	if account.CurrentAccountID == accountID {
+		c.tokens.setActiveAccountID(accountID)
+
+		return nil
+	}
+
+	if _, err := c.Session().SwitchActiveAccount(ctx, accountID); err != nil {
+		// the expired tokens are restored, so the requests aren't sent to the default account
+		// and the session is renewed again on the next request
+		c.tokens.setSessionTokens(expiredCST, expiredSecurityToken)
+		c.tokens.setActiveAccountID(accountID)
+
+		return NewSessionRenewalError(err)
+	}
+
+	return nil
+}
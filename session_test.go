@@ -1,9 +1,16 @@
 package capitalcom_test
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
 
 	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -95,3 +102,315 @@ func isAuthorized(w http.ResponseWriter, r *http.Request) bool {
 
 	return true
 }
+
+func TestClient_ReauthenticatesExpiredSession(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var sessionsCreated, pingsReceived atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			if sessionsCreated.Add(1) == 1 {
+				w.Header().Set(capitalcom.HeaderKeySecurityToken, "ExpiredSecurityToken")
+				w.Header().Set(capitalcom.HeaderTokenCST, "ExpiredCST")
+				_, _ = w.Write([]byte(`{}`))
+
+				return
+			}
+
+			handleSessionCreation(w, r)
+		case capitalcom.APIPathV1 + "/ping":
+			pingsReceived.Add(1)
+
+			if !isAuthorized(w, r) {
+				_, _ = w.Write([]byte(`{"errorCode":"error.invalid.session.token"}`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{ "status": "OK" }`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	// Act
+	got, err := underTest.Ping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "OK", got)
+	require.Equal(t, int32(2), sessionsCreated.Load())
+	require.Equal(t, int32(2), pingsReceived.Load())
+}
+
+func TestClient_ReauthenticatesOnInvalidSessionTokenCode(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var sessionsCreated, pingsReceived atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			sessionsCreated.Add(1)
+			handleSessionCreation(w, r)
+		case capitalcom.APIPathV1 + "/ping":
+			if pingsReceived.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errorCode":"error.invalid.session.token"}`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{ "status": "OK" }`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	// Act
+	got, err := underTest.Ping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "OK", got)
+	require.Equal(t, int32(2), sessionsCreated.Load())
+	require.Equal(t, int32(2), pingsReceived.Load())
+}
+
+func TestClient_ReauthenticationRestoresActiveAccount(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var (
+		sessionsCreated, pingsReceived atomic.Int32
+		mu                             sync.Mutex
+		switchedAccounts               []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == capitalcom.APIPathV1+"/session" && r.Method == http.MethodPost:
+			sessionsCreated.Add(1)
+			handleSessionCreation(w, r)
+		case r.URL.Path == capitalcom.APIPathV1+"/session" && r.Method == http.MethodPut:
+			if !isAuthorized(w, r) {
+				return
+			}
+
+			payload := struct {
+				AccountID string `json:"accountId"`
+			}{}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+
+			mu.Lock()
+			switchedAccounts = append(switchedAccounts, payload.AccountID)
+			mu.Unlock()
+
+			_, _ = w.Write([]byte(`{"dealingEnabled":true}`))
+		case r.URL.Path == capitalcom.APIPathV1+"/ping":
+			// the session expires once after switching the account
+			if pingsReceived.Add(1) == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{ "status": "OK" }`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	_, err = underTest.Session().SwitchActiveAccount(ctx, "12345678907654321")
+	require.NoError(t, err)
+
+	// Act
+	got, err := underTest.Ping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "OK", got)
+	require.Equal(t, int32(2), sessionsCreated.Load())
+	require.Equal(t, []string{"12345678907654321", "12345678907654321"}, switchedAccounts)
+}
+
+func TestClient_ReauthenticationKeepsActiveAccountOpenedByNewSession(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var (
+		sessionsCreated, pingsReceived atomic.Int32
+		mu                             sync.Mutex
+		switchedAccounts               []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == capitalcom.APIPathV1+"/session" && r.Method == http.MethodPost:
+			// the first renewed session opens on the selected account, the others on the default one
+			accountID := "12345678901234567"
+			if sessionsCreated.Add(1) == 2 {
+				accountID = "12345678907654321"
+			}
+
+			w.Header().Set(capitalcom.HeaderKeySecurityToken, expectedKeySecurityToken)
+			w.Header().Set(capitalcom.HeaderTokenCST, expectedCST)
+			_, _ = w.Write([]byte(`{"accountType":"CFD","currentAccountId":"` + accountID + `"}`))
+		case r.URL.Path == capitalcom.APIPathV1+"/session" && r.Method == http.MethodPut:
+			if !isAuthorized(w, r) {
+				return
+			}
+
+			payload := struct {
+				AccountID string `json:"accountId"`
+			}{}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+
+			mu.Lock()
+			switchedAccounts = append(switchedAccounts, payload.AccountID)
+			mu.Unlock()
+
+			_, _ = w.Write([]byte(`{"dealingEnabled":true}`))
+		case r.URL.Path == capitalcom.APIPathV1+"/ping":
+			// every other ping finds the session expired
+			if pingsReceived.Add(1)%2 == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			if !isAuthorized(w, r) {
+				return
+			}
+
+			_, _ = w.Write([]byte(`{ "status": "OK" }`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	_, err = underTest.Session().SwitchActiveAccount(ctx, "12345678907654321")
+	require.NoError(t, err)
+
+	// Act
+	_, firstErr := underTest.Ping(ctx)
+	_, secondErr := underTest.Ping(ctx)
+
+	// Assert
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	require.Equal(t, int32(3), sessionsCreated.Load())
+	require.Equal(t, []string{"12345678907654321", "12345678907654321"}, switchedAccounts)
+}
+
+func TestClient_DoesNotReauthenticateWhenDisabled(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var sessionsCreated atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			sessionsCreated.Add(1)
+			w.Header().Set(capitalcom.HeaderKeySecurityToken, "ExpiredSecurityToken")
+			w.Header().Set(capitalcom.HeaderTokenCST, "ExpiredCST")
+			_, _ = w.Write([]byte(`{}`))
+		case capitalcom.APIPathV1 + "/ping":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errorCode":"error.invalid.session.token"}`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	underTest := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithAutoReauthentication(false))
+
+	_, err := underTest.Session().CreateNew(ctx, false)
+	require.NoError(t, err)
+
+	var apiErr capitalcom.APIError
+
+	// Act
+	_, err = underTest.Ping(ctx)
+
+	// Assert
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	require.Equal(t, int32(1), sessionsCreated.Load())
+}
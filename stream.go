@@ -0,0 +1,481 @@
+package capitalcom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gromson/capitalcom/internal/websocket"
+)
+
+const (
+	streamingPath = "/connect"
+
+	// maxEpicsPerSubscription is a limit of epics per a single subscription message.
+	maxEpicsPerSubscription = 40
+
+	streamEventsBufferSize = 256
+
+	streamStatusOK = "OK"
+
+	subscriptionStatusProcessed = "PROCESSED"
+)
+
+const (
+	destinationQuote                 = "quote"
+	destinationPing                  = "ping"
+	destinationMarketDataSubscribe   = "marketData.subscribe"
+	destinationMarketDataUnsubscribe = "marketData.unsubscribe"
+)
+
+type (
+	streamRequest struct {
+		Destination   string `json:"destination"`
+		CorrelationID string `json:"correlationId"`
+		CST           string `json:"cst"`
+		SecurityToken string `json:"securityToken"`
+		Payload       any    `json:"payload,omitempty"`
+	}
+
+	streamMessage struct {
+		Status        string          `json:"status"`
+		Destination   string          `json:"destination"`
+		CorrelationID string          `json:"correlationId"`
+		Payload       json.RawMessage `json:"payload"`
+	}
+
+	marketDataSubscriptionPayload struct {
+		Epics []string `json:"epics"`
+	}
+
+	subscriptionResultPayload struct {
+		Subscriptions map[string]string `json:"subscriptions"`
+	}
+)
+
+// Quote is a real-time bid/offer update of a market.
+type Quote struct {
+	Epic      string    `json:"epic"`
+	Product   string    `json:"product"`
+	Bid       float64   `json:"bid"`
+	BidQty    float64   `json:"bidQty"`
+	Offer     float64   `json:"ofr"`
+	OfferQty  float64   `json:"ofrQty"` //nolint:tagliatelle
+	Timestamp time.Time `json:"-"`
+}
+
+func (q *Quote) UnmarshalJSON(data []byte) error {
+	type alias Quote
+
+	aux := &struct {
+		Timestamp int64 `json:"timestamp"`
+		*alias
+	}{
+		alias: (*alias)(q),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return NewResponsePayloadDecodingError(err)
+	}
+
+	q.Timestamp = time.UnixMilli(aux.Timestamp)
+
+	return nil
+}
+
+// Stream is a connection to the Capital.com streaming API. Quotes and OHLC bars are delivered on channels
+// which must be drained by the consumer, otherwise reading from the connection is blocked.
+// The connection is kept alive with pings and re-established when lost, active subscriptions are restored
+// after reconnecting, see Stream.States. The channels are closed once the stream is closed.
+type Stream struct {
+	client *Client
+	cfg    streamConfig
+
+	// ctx is cancelled when the stream is closed
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+
+	// dialer follows the network setup of the client's HTTP transport
+	dialer *websocket.Dialer
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	pending map[string]chan streamMessage
+	// connLost is closed when the current connection is lost
+	connLost chan struct{}
+
+	quoteSubscriptions  map[string]struct{}
+	candleSubscriptions map[candleKey]struct{}
+
+	correlationID atomic.Int64
+
+	quotes  chan Quote
+	candles chan Candle
+
+	statesMu     sync.Mutex
+	states       chan StreamStateEvent
+	statesClosed bool
+
+	// candleStates keeps the bars being merged, it is guarded by mu
+	candleStates map[candleKey]*candleState
+
+	// running is set once the connections are served by the run loop, which closes the channels then
+	running bool
+
+	done         chan struct{}
+	closeOnce    sync.Once
+	channelsOnce sync.Once
+	err          error
+}
+
+func newStream(c *Client, opts ...StreamOption) *Stream {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Stream{
+		client: c,
+		cfg:    defaultStreamConfig(),
+		ctx:    ctx,
+		cancel: cancel,
+		dialer: websocketDialer(c.httpClient),
+
+		pending:  make(map[string]chan streamMessage),
+		connLost: make(chan struct{}),
+
+		quoteSubscriptions:  make(map[string]struct{}),
+		candleSubscriptions: make(map[candleKey]struct{}),
+
+		quotes:  make(chan Quote, streamEventsBufferSize),
+		candles: make(chan Candle, streamEventsBufferSize),
+		states:  make(chan StreamStateEvent, streamStatesBufferSize),
+		done:    make(chan struct{}),
+
+		candleStates: make(map[candleKey]*candleState),
+	}
+
+	for _, opt := range opts {
+		opt(&s.cfg)
+	}
+
+	return s
+}
+
+// websocketDialer returns a dialer using the proxy, the TLS configuration and the dial function
+// of the HTTP client's transport. Transports other than http.Transport can't be reused,
+// the streaming connections are dialed directly then.
+func websocketDialer(httpClient *http.Client) *websocket.Dialer {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+
+	if httpClient != nil && httpClient.Transport != nil {
+		transport, ok = httpClient.Transport.(*http.Transport)
+	}
+
+	if !ok {
+		return &websocket.Dialer{}
+	}
+
+	return &websocket.Dialer{
+		Proxy:           transport.Proxy,
+		TLSClientConfig: transport.TLSClientConfig,
+		NetDialContext:  transport.DialContext,
+	}
+}
+
+// Connect opens the connection to the streaming API. A session must be created before connecting.
+func (s *Stream) Connect(ctx context.Context) error {
+	s.mu.Lock()
+	err := s.checkConnectable()
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	// the lock isn't held while dialing, so the stream can be used and closed in the meantime
+	conn, err := s.dialer.Dial(ctx, s.url(), nil)
+	if err != nil {
+		return NewStreamConnectionError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkConnectable(); err != nil {
+		_ = conn.Close()
+
+		return err
+	}
+
+	s.conn = conn
+	s.running = true
+
+	go s.run(conn)
+
+	s.emitState(StreamStateConnected, nil, 0)
+
+	return nil
+}
+
+// checkConnectable returns an error if the stream is closed or already connected, mu must be held.
+func (s *Stream) checkConnectable() error {
+	if s.isClosed() {
+		return ErrStreamClosed
+	}
+
+	// the run loop keeps serving the stream while it is reconnecting
+	if s.running {
+		return ErrStreamAlreadyConnected
+	}
+
+	return nil
+}
+
+// SubscribeQuotes subscribes to real-time quotes of the given epics, see Stream.Quotes.
+func (s *Stream) SubscribeQuotes(ctx context.Context, epics ...string) error {
+	for chunk := range slices.Chunk(epics, maxEpicsPerSubscription) {
+		if err := s.subscribeQuotes(ctx, chunk); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+
+		for _, epic := range chunk {
+			s.quoteSubscriptions[epic] = struct{}{}
+		}
+
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// UnsubscribeQuotes cancels real-time quotes subscription of the given epics.
+func (s *Stream) UnsubscribeQuotes(ctx context.Context, epics ...string) error {
+	for chunk := range slices.Chunk(epics, maxEpicsPerSubscription) {
+		if _, err := s.request(ctx, destinationMarketDataUnsubscribe, marketDataSubscriptionPayload{Epics: chunk}); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+
+		for _, epic := range chunk {
+			delete(s.quoteSubscriptions, epic)
+		}
+
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Quotes returns a channel of the subscribed quotes.
+func (s *Stream) Quotes() <-chan Quote {
+	return s.quotes
+}
+
+// Done returns a channel that is closed when the stream is closed.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that caused the stream to close, it returns nil if the stream has been closed by Close.
+func (s *Stream) Err() error {
+	select {
+	case <-s.done:
+	default:
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Close closes the stream and the underlying connection.
+func (s *Stream) Close() error {
+	return s.shutdown(nil)
+}
+
+func (s *Stream) shutdown(cause error) error {
+	var err error
+
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.err = cause
+		close(s.done)
+		s.cancel()
+
+		if s.conn != nil {
+			err = s.conn.Close()
+		}
+
+		// without the run loop nothing else closes the channels
+		if !s.running {
+			s.emitState(StreamStateClosed, nil, 0)
+			s.closeChannels()
+		}
+	})
+
+	return err
+}
+
+func (s *Stream) url() string {
+	host := s.client.streamingHost
+
+	if host == "" {
+		host = s.client.tokens.currentStreamingHost()
+	}
+
+	if host == "" {
+		host = StreamingHost
+	}
+
+	return strings.TrimSuffix(host, "/") + streamingPath
+}
+
+func (s *Stream) subscribeQuotes(ctx context.Context, epics []string) error {
+	res, err := s.request(ctx, destinationMarketDataSubscribe, marketDataSubscriptionPayload{Epics: epics})
+	if err != nil {
+		return err
+	}
+
+	return checkSubscriptionResult(res)
+}
+
+func (s *Stream) request(ctx context.Context, destination string, payload any) (*streamMessage, error) {
+	correlationID := strconv.FormatInt(s.correlationID.Add(1), 10)
+	cst, securityToken := s.client.tokens.sessionTokens()
+
+	data, err := json.Marshal(streamRequest{
+		Destination:   destination,
+		CorrelationID: correlationID,
+		CST:           cst,
+		SecurityToken: securityToken,
+		Payload:       payload,
+	})
+	if err != nil {
+		return nil, NewRequestPayloadEncodingError(err)
+	}
+
+	select {
+	case <-s.done:
+		return nil, ErrStreamClosed
+	default:
+	}
+
+	resCh := make(chan streamMessage, 1)
+
+	s.mu.Lock()
+	conn := s.conn
+	connLost := s.connLost
+	s.pending[correlationID] = resCh
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, correlationID)
+		s.mu.Unlock()
+	}()
+
+	if conn == nil {
+		return nil, NewStreamConnectionError(ErrStreamNotConnected)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		if errors.Is(err, websocket.ErrClosed) {
+			return nil, NewStreamConnectionError(ErrStreamNotConnected)
+		}
+
+		return nil, NewStreamConnectionError(err)
+	}
+
+	select {
+	case res := <-resCh:
+		if res.Status != streamStatusOK {
+			return nil, NewStreamError(destination, res.Status, nil)
+		}
+
+		return &res, nil
+	case <-s.done:
+		return nil, ErrStreamClosed
+	case <-connLost:
+		return nil, NewStreamConnectionError(ErrStreamNotConnected)
+	case <-ctx.Done():
+		return nil, NewStreamConnectionError(ctx.Err())
+	}
+}
+
+// dispatch routes the message to a waiting request or to an event channel,
+// it returns false if the stream has been closed while delivering the message.
+func (s *Stream) dispatch(msg streamMessage) bool {
+	if msg.CorrelationID != "" {
+		s.mu.Lock()
+		resCh, ok := s.pending[msg.CorrelationID]
+		s.mu.Unlock()
+
+		// a repeated reply to the same request is dropped, so it can't block the read loop
+		if ok {
+			select {
+			case resCh <- msg:
+			default:
+				s.client.logger.With("correlationId", msg.CorrelationID).Warn("dropping a repeated streaming reply")
+			}
+
+			return true
+		}
+	}
+
+	switch msg.Destination {
+	case destinationQuote:
+		quote := Quote{}
+
+		if err := json.Unmarshal(msg.Payload, &quote); err != nil {
+			s.client.logger.With("error", err).Error("failed to decode a quote")
+
+			return true
+		}
+
+		select {
+		case s.quotes <- quote:
+		case <-s.done:
+			return false
+		}
+	case destinationOHLC:
+		return s.dispatchCandle(msg.Payload)
+	default:
+		s.client.logger.With("destination", msg.Destination).Debug("skipping a streaming message")
+	}
+
+	return true
+}
+
+func checkSubscriptionResult(res *streamMessage) error {
+	result := subscriptionResultPayload{}
+
+	if err := json.Unmarshal(res.Payload, &result); err != nil {
+		return NewResponsePayloadDecodingError(err)
+	}
+
+	rejected := make(map[string]string)
+
+	for epic, status := range result.Subscriptions {
+		if status != subscriptionStatusProcessed {
+			rejected[epic] = status
+		}
+	}
+
+	if len(rejected) > 0 {
+		return NewStreamError(res.Destination, res.Status, rejected)
+	}
+
+	return nil
+}
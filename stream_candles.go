@@ -0,0 +1,196 @@
+package capitalcom
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+const (
+	destinationOHLC                      = "ohlc.event"
+	destinationOHLCMarketDataSubscribe   = "OHLCMarketData.subscribe"
+	destinationOHLCMarketDataUnsubscribe = "OHLCMarketData.unsubscribe"
+
+	candleTypeClassic = "classic"
+
+	candlePriceTypeBid = "bid"
+	candlePriceTypeAsk = "ask"
+)
+
+type (
+	ohlcSubscriptionPayload struct {
+		Epics       []string     `json:"epics"`
+		Resolutions []Resolution `json:"resolutions"`
+		Type        string       `json:"type,omitempty"`
+		Types       []string     `json:"types,omitempty"`
+	}
+
+	ohlcEventPayload struct {
+		Resolution Resolution `json:"resolution"`
+		Epic       string     `json:"epic"`
+		Type       string     `json:"type"`
+		PriceType  string     `json:"priceType"`
+		Time       int64      `json:"t"`
+		Open       float64    `json:"o"`
+		High       float64    `json:"h"`
+		Low        float64    `json:"l"`
+		Close      float64    `json:"c"`
+	}
+)
+
+// Candle is a real-time update of a forming OHLC bar. The Price has the same shape
+// as the bars returned by the price history, both bid and ask prices are always set,
+// SnapshotTime is in UTC as the streaming API doesn't provide the local time of a bar.
+type Candle struct {
+	Epic       string
+	Resolution Resolution
+	Price      Price
+}
+
+type candleKey struct {
+	epic       string
+	resolution Resolution
+}
+
+// candleState merges separate bid and ask updates of the same bar.
+type candleState struct {
+	price  Price
+	hasBid bool
+	hasAsk bool
+}
+
+// SubscribeCandles subscribes to real-time OHLC bars of the given epics for every given resolution,
+// see Stream.Candles.
+func (s *Stream) SubscribeCandles(ctx context.Context, epics []string, resolutions ...Resolution) error {
+	for chunk := range slices.Chunk(epics, maxEpicsPerSubscription) {
+		if err := s.subscribeCandles(ctx, chunk, resolutions); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+
+		for _, epic := range chunk {
+			for _, resolution := range resolutions {
+				s.candleSubscriptions[candleKey{epic: epic, resolution: resolution}] = struct{}{}
+			}
+		}
+
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// UnsubscribeCandles cancels OHLC bars subscription of the given epics for every given resolution.
+func (s *Stream) UnsubscribeCandles(ctx context.Context, epics []string, resolutions ...Resolution) error {
+	for chunk := range slices.Chunk(epics, maxEpicsPerSubscription) {
+		if _, err := s.request(ctx, destinationOHLCMarketDataUnsubscribe, ohlcSubscriptionPayload{
+			Epics:       chunk,
+			Resolutions: resolutions,
+			Types:       []string{candleTypeClassic},
+		}); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+
+		for _, epic := range chunk {
+			for _, resolution := range resolutions {
+				delete(s.candleSubscriptions, candleKey{epic: epic, resolution: resolution})
+				delete(s.candleStates, candleKey{epic: epic, resolution: resolution})
+			}
+		}
+
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Candles returns a channel of the subscribed OHLC bars updates.
+func (s *Stream) Candles() <-chan Candle {
+	return s.candles
+}
+
+// mergeCandle applies the bid or ask bar update and returns the merged candle
+// once both sides of the bar are known.
+func (s *Stream) mergeCandle(event ohlcEventPayload) (Candle, bool) {
+	key := candleKey{epic: event.Epic, resolution: event.Resolution}
+	snapshotTime := time.UnixMilli(event.Time).UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.candleStates[key]
+	if !ok || !state.price.SnapshotTimeUTC.Equal(snapshotTime) {
+		state = &candleState{
+			price: Price{
+				SnapshotTime:    snapshotTime,
+				SnapshotTimeUTC: snapshotTime,
+			},
+		}
+		s.candleStates[key] = state
+	}
+
+	switch event.PriceType {
+	case candlePriceTypeBid:
+		state.price.OpenPrice.Bid = event.Open
+		state.price.HighPrice.Bid = event.High
+		state.price.LowPrice.Bid = event.Low
+		state.price.ClosePrice.Bid = event.Close
+		state.hasBid = true
+	case candlePriceTypeAsk:
+		state.price.OpenPrice.Ask = event.Open
+		state.price.HighPrice.Ask = event.High
+		state.price.LowPrice.Ask = event.Low
+		state.price.ClosePrice.Ask = event.Close
+		state.hasAsk = true
+	}
+
+	if !state.hasBid || !state.hasAsk {
+		return Candle{}, false
+	}
+
+	return Candle{
+		Epic:       event.Epic,
+		Resolution: event.Resolution,
+		Price:      state.price,
+	}, true
+}
+
+func (s *Stream) subscribeCandles(ctx context.Context, epics []string, resolutions []Resolution) error {
+	res, err := s.request(ctx, destinationOHLCMarketDataSubscribe, ohlcSubscriptionPayload{
+		Epics:       epics,
+		Resolutions: resolutions,
+		Type:        candleTypeClassic,
+	})
+	if err != nil {
+		return err
+	}
+
+	return checkSubscriptionResult(res)
+}
+
+func (s *Stream) dispatchCandle(payload json.RawMessage) bool {
+	event := ohlcEventPayload{}
+
+	if err := json.Unmarshal(payload, &event); err != nil {
+		s.client.logger.With("error", err).Error("failed to decode an OHLC bar")
+
+		return true
+	}
+
+	candle, ok := s.mergeCandle(event)
+	if !ok {
+		return true
+	}
+
+	select {
+	case s.candles <- candle:
+	case <-s.done:
+		return false
+	}
+
+	return true
+}
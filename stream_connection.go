@@ -0,0 +1,403 @@
+package capitalcom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/gromson/capitalcom/internal/websocket"
+)
+
+const (
+	defaultStreamPingInterval = time.Minute
+	defaultStreamPingTimeout  = 10 * time.Second
+	defaultStreamMinBackoff   = time.Second
+	defaultStreamMaxBackoff   = time.Minute
+
+	streamStatesBufferSize = 16
+
+	// streamBackoffJitter is a max share of a delay randomly added to it.
+	streamBackoffJitter = 0.2
+)
+
+// StreamState is a state of the streaming API connection.
+type StreamState string
+
+const (
+	StreamStateConnected    StreamState = "CONNECTED"
+	StreamStateDisconnected StreamState = "DISCONNECTED"
+	StreamStateReconnecting StreamState = "RECONNECTING"
+	StreamStateClosed       StreamState = "CLOSED"
+)
+
+// StreamStateEvent reports a change of the streaming API connection state.
+type StreamStateEvent struct {
+	State StreamState
+	// Err is the cause of a disconnection, a failed reconnection attempt or a failed subscriptions restoring
+	Err error
+	// Attempt is a number of the reconnection attempt, it is set for the RECONNECTING state only
+	Attempt int
+	Time    time.Time
+}
+
+type streamConfig struct {
+	pingInterval         time.Duration
+	pingTimeout          time.Duration
+	reconnect            bool
+	minBackoff           time.Duration
+	maxBackoff           time.Duration
+	maxReconnectAttempts int
+}
+
+func defaultStreamConfig() streamConfig {
+	return streamConfig{
+		pingInterval: defaultStreamPingInterval,
+		pingTimeout:  defaultStreamPingTimeout,
+		reconnect:    true,
+		minBackoff:   defaultStreamMinBackoff,
+		maxBackoff:   defaultStreamMaxBackoff,
+	}
+}
+
+// StreamOption is a functional for setting the config option for the stream.
+type StreamOption func(*streamConfig)
+
+// WithStreamPingInterval sets the interval of pings keeping the streaming session alive,
+// a non-positive interval is ignored.
+func WithStreamPingInterval(interval time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		if interval > 0 {
+			c.pingInterval = interval
+		}
+	}
+}
+
+// WithStreamPingTimeout sets how long to wait for a ping response before the connection is considered dead.
+// It is also used as a timeout for establishing a connection. A non-positive timeout is ignored.
+func WithStreamPingTimeout(timeout time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		if timeout > 0 {
+			c.pingTimeout = timeout
+		}
+	}
+}
+
+// WithStreamReconnect enables or disables reconnecting when the connection is lost, it is enabled by default.
+func WithStreamReconnect(enabled bool) StreamOption {
+	return func(c *streamConfig) {
+		c.reconnect = enabled
+	}
+}
+
+// WithStreamReconnectBackoff sets the delay range between reconnection attempts,
+// the delay starts from minDelay and doubles after every failed attempt up to maxDelay.
+// Non-positive delays are ignored, maxDelay is raised to minDelay if it is lower.
+func WithStreamReconnectBackoff(minDelay, maxDelay time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		if minDelay > 0 {
+			c.minBackoff = minDelay
+		}
+
+		if maxDelay > 0 {
+			c.maxBackoff = maxDelay
+		}
+
+		c.maxBackoff = max(c.maxBackoff, c.minBackoff)
+	}
+}
+
+// WithStreamMaxReconnectAttempts limits the number of consecutive reconnection attempts, 0 means no limit.
+func WithStreamMaxReconnectAttempts(attempts int) StreamOption {
+	return func(c *streamConfig) {
+		c.maxReconnectAttempts = attempts
+	}
+}
+
+// States returns a channel of the connection state changes. Events are dropped if the channel buffer is full.
+func (s *Stream) States() <-chan StreamStateEvent {
+	return s.states
+}
+
+func (s *Stream) emitState(state StreamState, err error, attempt int) {
+	s.statesMu.Lock()
+	defer s.statesMu.Unlock()
+
+	if s.statesClosed {
+		return
+	}
+
+	select {
+	case s.states <- StreamStateEvent{State: state, Err: err, Attempt: attempt, Time: time.Now()}:
+	default:
+		s.client.logger.With("state", state).Warn("dropping a stream state event, the channel is full")
+	}
+}
+
+// run serves connections until the stream is closed or the connection can't be re-established.
+func (s *Stream) run(conn *websocket.Conn) {
+	defer s.closeChannels()
+
+	for {
+		err := s.serve(conn)
+
+		s.connectionLost()
+
+		if s.isClosed() {
+			s.emitState(StreamStateClosed, nil, 0)
+
+			return
+		}
+
+		s.client.logger.With("error", err).Warn("the streaming API connection is lost")
+		s.emitState(StreamStateDisconnected, err, 0)
+
+		if !s.cfg.reconnect {
+			_ = s.shutdown(NewStreamConnectionError(err))
+			s.emitState(StreamStateClosed, err, 0)
+
+			return
+		}
+
+		conn, err = s.reconnect()
+		if err != nil {
+			if s.isClosed() {
+				err = nil
+			}
+
+			_ = s.shutdown(err)
+			s.emitState(StreamStateClosed, err, 0)
+
+			return
+		}
+
+		go s.resubscribe(conn)
+	}
+}
+
+// serve reads the connection and keeps it alive with pings until the connection fails.
+func (s *Stream) serve(conn *websocket.Conn) error {
+	stopPing := make(chan struct{})
+	pingDone := make(chan struct{})
+
+	go func() {
+		defer close(pingDone)
+
+		s.pingLoop(conn, stopPing)
+	}()
+
+	defer func() {
+		close(stopPing)
+		_ = conn.Close()
+		<-pingDone
+	}()
+
+	for {
+		// a ping response is expected at least every ping interval, a silent connection is considered dead
+		if err := conn.SetReadDeadline(time.Now().Add(s.cfg.pingInterval + s.cfg.pingTimeout)); err != nil {
+			return err
+		}
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		msg := streamMessage{}
+
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.client.logger.With("error", err).Error("failed to decode a streaming message")
+
+			continue
+		}
+
+		if !s.dispatch(msg) {
+			return ErrStreamClosed
+		}
+	}
+}
+
+func (s *Stream) pingLoop(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(s.cfg.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, s.cfg.pingTimeout)
+		_, err := s.request(ctx, destinationPing, nil)
+
+		cancel()
+
+		if err != nil && !errors.Is(err, ErrStreamClosed) {
+			s.client.logger.With("error", err).Warn("streaming API ping failed, dropping the connection")
+
+			_ = conn.Close()
+
+			return
+		}
+	}
+}
+
+// reconnect establishes a new connection with a backoff between attempts.
+func (s *Stream) reconnect() (*websocket.Conn, error) {
+	delay := s.cfg.minBackoff
+
+	var lastErr error
+
+	for attempt := 1; s.cfg.maxReconnectAttempts == 0 || attempt <= s.cfg.maxReconnectAttempts; attempt++ {
+		s.emitState(StreamStateReconnecting, lastErr, attempt)
+
+		timer := time.NewTimer(withJitter(delay))
+
+		select {
+		case <-s.done:
+			timer.Stop()
+
+			return nil, ErrStreamClosed
+		case <-timer.C:
+		}
+
+		conn, err := s.dial()
+		if err == nil {
+			return conn, nil
+		}
+
+		if errors.Is(err, ErrStreamClosed) {
+			return nil, err
+		}
+
+		s.client.logger.With("error", err, "attempt", attempt).Warn("failed to reconnect to the streaming API")
+
+		lastErr = err
+		delay = min(delay*2, s.cfg.maxBackoff) //nolint:mnd
+	}
+
+	return nil, NewStreamConnectionError(lastErr)
+}
+
+func (s *Stream) dial() (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.cfg.pingTimeout)
+	defer cancel()
+
+	// pinging the API refreshes the session tokens, an expired session is renewed by the client
+	if _, err := s.client.Ping(ctx); err != nil {
+		return nil, err
+	}
+
+	conn, err := s.dialer.Dial(ctx, s.url(), nil)
+	if err != nil {
+		return nil, NewStreamConnectionError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isClosed() {
+		_ = conn.Close()
+
+		return nil, ErrStreamClosed
+	}
+
+	s.conn = conn
+
+	return conn, nil
+}
+
+// resubscribe restores active subscriptions on a new connection.
+func (s *Stream) resubscribe(conn *websocket.Conn) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.cfg.pingTimeout)
+	defer cancel()
+
+	epics, candles := s.subscriptions()
+
+	var errs []error
+
+	for chunk := range slices.Chunk(epics, maxEpicsPerSubscription) {
+		errs = append(errs, s.subscribeQuotes(ctx, chunk))
+	}
+
+	for resolution, candleEpics := range candles {
+		for chunk := range slices.Chunk(candleEpics, maxEpicsPerSubscription) {
+			errs = append(errs, s.subscribeCandles(ctx, chunk, []Resolution{resolution}))
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	var connErr StreamConnectionError
+
+	if errors.As(err, &connErr) {
+		s.client.logger.With("error", err).Warn("failed to restore streaming subscriptions, dropping the connection")
+
+		_ = conn.Close()
+
+		return
+	}
+
+	s.emitState(StreamStateConnected, err, 0)
+}
+
+// subscriptions returns active quote subscriptions and candle subscriptions grouped by resolution.
+func (s *Stream) subscriptions() ([]string, map[Resolution][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	epics := make([]string, 0, len(s.quoteSubscriptions))
+
+	for epic := range s.quoteSubscriptions {
+		epics = append(epics, epic)
+	}
+
+	candles := make(map[Resolution][]string)
+
+	for key := range s.candleSubscriptions {
+		candles[key.resolution] = append(candles[key.resolution], key.epic)
+	}
+
+	return epics, candles
+}
+
+func (s *Stream) connectionLost() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	close(s.connLost)
+	s.connLost = make(chan struct{})
+	s.conn = nil
+
+	// the bars of the lost connection aren't completed by the next one
+	clear(s.candleStates)
+}
+
+func (s *Stream) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Stream) closeChannels() {
+	s.channelsOnce.Do(func() {
+		close(s.quotes)
+		close(s.candles)
+
+		s.statesMu.Lock()
+		defer s.statesMu.Unlock()
+
+		s.statesClosed = true
+		close(s.states)
+	})
+}
+
+func withJitter(delay time.Duration) time.Duration {
+	return delay + time.Duration(rand.Float64()*streamBackoffJitter*float64(delay)) //nolint:gosec
+}
@@ -0,0 +1,818 @@
+package capitalcom_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gromson/capitalcom"
+	"github.com/gromson/capitalcom/internal/websocket"
+	"github.com/gromson/capitalcom/internal/wstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamingRequest struct {
+	Destination   string          `json:"destination"`
+	CorrelationID string          `json:"correlationId"`
+	CST           string          `json:"cst"`
+	SecurityToken string          `json:"securityToken"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// streamingHandler is a fake streaming API calling the handler for every received request,
+// messages returned by the handler are sent back to the client.
+func streamingHandler(t *testing.T, handle func(req streamingRequest) []string) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/connect" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			req := streamingRequest{}
+			if err := json.Unmarshal(data, &req); err != nil {
+				return
+			}
+
+			for _, message := range handle(req) {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func newStreamingClient(t *testing.T, streamingSrv *httptest.Server) *capitalcom.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case capitalcom.APIPathV1 + "/session":
+			handleSessionCreation(w, r)
+		case capitalcom.APIPathV1 + "/ping":
+			if !isAuthorized(w, r) {
+				return
+			}
+
+			_, _ = w.Write([]byte(`{ "status": "OK" }`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		srv.Close()
+	})
+
+	client := capitalcom.NewClient(expectedAPIKey,
+		identifier,
+		password,
+		capitalcom.WithHTTPClient(srv.Client()),
+		capitalcom.WithHost(srv.URL),
+		capitalcom.WithStreamingHost("ws"+strings.TrimPrefix(streamingSrv.URL, "http")))
+
+	_, err := client.Session().CreateNew(context.Background(), false)
+	require.NoError(t, err)
+
+	return client
+}
+
+func subscriptionResponse(req streamingRequest, status string) string {
+	payload := struct {
+		Epics []string `json:"epics"`
+	}{}
+
+	_ = json.Unmarshal(req.Payload, &payload)
+
+	subscriptions := make(map[string]string, len(payload.Epics))
+	for _, epic := range payload.Epics {
+		subscriptions[epic] = status
+	}
+
+	res, _ := json.Marshal(map[string]any{
+		"status":        "OK",
+		"destination":   req.Destination,
+		"correlationId": req.CorrelationID,
+		"payload":       map[string]any{"subscriptions": subscriptions},
+	})
+
+	return string(res)
+}
+
+func TestStream_SubscribeQuotes(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	requests := make(chan streamingRequest, 10)
+
+	streamingSrv := httptest.NewServer(streamingHandler(t, func(req streamingRequest) []string {
+		requests <- req
+
+		if req.Destination != "marketData.subscribe" {
+			return []string{`{"status":"OK","destination":"` + req.Destination + `","correlationId":"` + req.CorrelationID + `"}`}
+		}
+
+		return []string{
+			subscriptionResponse(req, "PROCESSED"),
+			`{"status":"OK","destination":"quote","payload":{"epic":"OIL_CRUDE","product":"CFD",` +
+				`"bid":93.87,"bidQty":4976.0,"ofr":93.9,"ofrQty":5000.0,"timestamp":1660297144000}}`,
+		}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream()
+	require.NoError(t, underTest.Connect(ctx))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	// Act
+	err := underTest.SubscribeQuotes(ctx, "OIL_CRUDE")
+
+	// Assert
+	require.NoError(t, err)
+
+	req := <-requests
+	assert.Equal(t, "marketData.subscribe", req.Destination)
+	assert.Equal(t, expectedCST, req.CST)
+	assert.Equal(t, expectedKeySecurityToken, req.SecurityToken)
+	assert.JSONEq(t, `{"epics":["OIL_CRUDE"]}`, string(req.Payload))
+
+	select {
+	case quote := <-underTest.Quotes():
+		assert.Equal(t, capitalcom.Quote{
+			Epic:      "OIL_CRUDE",
+			Product:   "CFD",
+			Bid:       93.87,
+			BidQty:    4976,
+			Offer:     93.9,
+			OfferQty:  5000,
+			Timestamp: time.UnixMilli(1660297144000),
+		}, quote)
+	case <-time.After(time.Second):
+		t.Fatal("no quote received")
+	}
+
+	require.NoError(t, underTest.UnsubscribeQuotes(ctx, "OIL_CRUDE"))
+	assert.Equal(t, "marketData.unsubscribe", (<-requests).Destination)
+}
+
+func TestStream_SubscribeQuotesReturnsRejectedEpics(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	streamingSrv := httptest.NewServer(streamingHandler(t, func(req streamingRequest) []string {
+		return []string{subscriptionResponse(req, "ERROR.invalid.epic")}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream()
+	require.NoError(t, underTest.Connect(ctx))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	var streamErr capitalcom.StreamError
+
+	// Act
+	err := underTest.SubscribeQuotes(ctx, "UNKNOWN")
+
+	// Assert
+	require.ErrorAs(t, err, &streamErr)
+	require.Equal(t, map[string]string{"UNKNOWN": "ERROR.invalid.epic"}, streamErr.Rejected())
+}
+
+func TestStream_CloseClosesEventChannels(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	streamingSrv := httptest.NewServer(streamingHandler(t, func(streamingRequest) []string {
+		return nil
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream()
+	require.NoError(t, underTest.Connect(ctx))
+
+	// Act
+	require.NoError(t, underTest.Close())
+
+	// Assert
+	_, ok := <-underTest.Quotes()
+	require.False(t, ok)
+	require.NoError(t, underTest.Err())
+	require.ErrorIs(t, underTest.SubscribeQuotes(ctx, "OIL_CRUDE"), capitalcom.ErrStreamClosed)
+}
+
+func TestStream_CloseWithoutConnectClosesEventChannels(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	underTest := capitalcom.NewClient(expectedAPIKey, identifier, password).Stream()
+
+	// Act
+	err := underTest.Close()
+
+	// Assert
+	require.NoError(t, err)
+
+	_, ok := <-underTest.Quotes()
+	require.False(t, ok)
+
+	_, ok = <-underTest.Candles()
+	require.False(t, ok)
+
+	event, ok := <-underTest.States()
+	require.True(t, ok)
+	require.Equal(t, capitalcom.StreamStateClosed, event.State)
+
+	_, ok = <-underTest.States()
+	require.False(t, ok)
+}
+
+func TestStream_CloseDoesNotWaitForConnect(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	handshakeStarted := make(chan struct{})
+	release := make(chan struct{})
+
+	// the handshake hangs until it is released
+	streamingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(handshakeStarted)
+		<-release
+
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		_, _, _ = conn.ReadMessage()
+	}))
+
+	t.Cleanup(func() {
+		close(release)
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream()
+
+	connectErr := make(chan error, 1)
+
+	go func() {
+		connectErr <- underTest.Connect(context.Background())
+	}()
+
+	<-handshakeStarted
+
+	// Act
+	closed := make(chan error, 1)
+
+	go func() {
+		closed <- underTest.Close()
+	}()
+
+	// Assert
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close is blocked by Connect")
+	}
+
+	require.NoError(t, underTest.Err())
+
+	release <- struct{}{}
+
+	require.ErrorIs(t, <-connectErr, capitalcom.ErrStreamClosed)
+}
+
+func TestStream_IgnoresRepeatedReplies(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	streamingSrv := httptest.NewServer(streamingHandler(t, func(req streamingRequest) []string {
+		return []string{
+			subscriptionResponse(req, "PROCESSED"),
+			subscriptionResponse(req, "PROCESSED"),
+			subscriptionResponse(req, "PROCESSED"),
+			`{"status":"OK","destination":"quote","payload":{"epic":"OIL_CRUDE","bid":93.87,"ofr":93.9}}`,
+		}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream()
+	require.NoError(t, underTest.Connect(ctx))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	// Act
+	err := underTest.SubscribeQuotes(ctx, "OIL_CRUDE")
+
+	// Assert
+	require.NoError(t, err)
+
+	select {
+	case quote := <-underTest.Quotes():
+		require.Equal(t, "OIL_CRUDE", quote.Epic)
+	case <-time.After(time.Second):
+		t.Fatal("the read loop is blocked by the repeated reply")
+	}
+}
+
+func TestStream_IgnoresNonPositivePingInterval(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	streamingSrv := httptest.NewServer(streamingHandler(t, func(req streamingRequest) []string {
+		return []string{subscriptionResponse(req, "PROCESSED")}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream(
+		capitalcom.WithStreamPingInterval(0),
+		capitalcom.WithStreamPingTimeout(-time.Second))
+
+	// Act
+	require.NoError(t, underTest.Connect(ctx))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	// Assert
+	require.Equal(t, capitalcom.StreamStateConnected, (<-underTest.States()).State)
+	require.NoError(t, underTest.SubscribeQuotes(ctx, "OIL_CRUDE"))
+
+	select {
+	case state := <-underTest.States():
+		t.Fatalf("unexpected state %s", state.State)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStream_IgnoresNonPositiveReconnectBackoff(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var connections atomic.Int32
+
+	// only the first connection is accepted and it drops right away
+	streamingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if connections.Add(1) > 1 {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		_ = conn.Close()
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream(capitalcom.WithStreamReconnectBackoff(0, -time.Second))
+
+	// Act
+	require.NoError(t, underTest.Connect(context.Background()))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	// Assert
+	for _, expectedState := range []capitalcom.StreamState{
+		capitalcom.StreamStateConnected,
+		capitalcom.StreamStateDisconnected,
+		capitalcom.StreamStateReconnecting,
+	} {
+		select {
+		case state := <-underTest.States():
+			require.Equal(t, expectedState, state.State)
+		case <-time.After(time.Second):
+			t.Fatalf("state %s is not reported", expectedState)
+		}
+	}
+
+	// the first attempt waits for the default delay of a second
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int32(1), connections.Load())
+}
+
+func TestStream_ConnectWhileReconnectingFails(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var connections atomic.Int32
+
+	// only the first connection is accepted and it drops right away
+	streamingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if connections.Add(1) > 1 {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		_ = conn.Close()
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream()
+	require.NoError(t, underTest.Connect(context.Background()))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	for _, expectedState := range []capitalcom.StreamState{
+		capitalcom.StreamStateConnected,
+		capitalcom.StreamStateDisconnected,
+		capitalcom.StreamStateReconnecting,
+	} {
+		select {
+		case state := <-underTest.States():
+			require.Equal(t, expectedState, state.State)
+		case <-time.After(time.Second):
+			t.Fatalf("state %s is not reported", expectedState)
+		}
+	}
+
+	// Act
+	err := underTest.Connect(context.Background())
+
+	// Assert
+	require.ErrorIs(t, err, capitalcom.ErrStreamAlreadyConnected)
+	require.Equal(t, int32(1), connections.Load())
+}
+
+func TestStream_SubscribeCandlesMergesBidAndAsk(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	requests := make(chan streamingRequest, 10)
+
+	streamingSrv := httptest.NewServer(streamingHandler(t, func(req streamingRequest) []string {
+		requests <- req
+
+		return []string{
+			subscriptionResponse(req, "PROCESSED"),
+			`{"status":"OK","destination":"ohlc.event","payload":{"resolution":"MINUTE","epic":"OIL_CRUDE",` +
+				`"type":"classic","priceType":"bid","t":1671714660000,"h":79.305,"l":79.295,"o":79.3,"c":79.305}}`,
+			`{"status":"OK","destination":"ohlc.event","payload":{"resolution":"MINUTE","epic":"OIL_CRUDE",` +
+				`"type":"classic","priceType":"ask","t":1671714660000,"h":79.335,"l":79.325,"o":79.33,"c":79.335}}`,
+			`{"status":"OK","destination":"ohlc.event","payload":{"resolution":"MINUTE","epic":"OIL_CRUDE",` +
+				`"type":"classic","priceType":"bid","t":1671714660000,"h":79.405,"l":79.295,"o":79.3,"c":79.4}}`,
+		}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream()
+	require.NoError(t, underTest.Connect(ctx))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	snapshotTime := time.Date(2022, 12, 22, 13, 11, 0, 0, time.UTC)
+
+	// Act
+	err := underTest.SubscribeCandles(ctx, []string{"OIL_CRUDE"}, capitalcom.ResolutionMinute)
+
+	// Assert
+	require.NoError(t, err)
+
+	req := <-requests
+	assert.Equal(t, "OHLCMarketData.subscribe", req.Destination)
+	assert.JSONEq(t, `{"epics":["OIL_CRUDE"],"resolutions":["MINUTE"],"type":"classic"}`, string(req.Payload))
+
+	expected := []capitalcom.Candle{
+		{
+			Epic:       "OIL_CRUDE",
+			Resolution: capitalcom.ResolutionMinute,
+			Price: capitalcom.Price{
+				SnapshotTime:    snapshotTime,
+				SnapshotTimeUTC: snapshotTime,
+				OpenPrice:       capitalcom.PriceData{Bid: 79.3, Ask: 79.33},
+				ClosePrice:      capitalcom.PriceData{Bid: 79.305, Ask: 79.335},
+				HighPrice:       capitalcom.PriceData{Bid: 79.305, Ask: 79.335},
+				LowPrice:        capitalcom.PriceData{Bid: 79.295, Ask: 79.325},
+			},
+		},
+		{
+			Epic:       "OIL_CRUDE",
+			Resolution: capitalcom.ResolutionMinute,
+			Price: capitalcom.Price{
+				SnapshotTime:    snapshotTime,
+				SnapshotTimeUTC: snapshotTime,
+				OpenPrice:       capitalcom.PriceData{Bid: 79.3, Ask: 79.33},
+				ClosePrice:      capitalcom.PriceData{Bid: 79.4, Ask: 79.335},
+				HighPrice:       capitalcom.PriceData{Bid: 79.405, Ask: 79.335},
+				LowPrice:        capitalcom.PriceData{Bid: 79.295, Ask: 79.325},
+			},
+		},
+	}
+
+	for _, expectedCandle := range expected {
+		select {
+		case candle := <-underTest.Candles():
+			assert.Equal(t, expectedCandle, candle)
+		case <-time.After(time.Second):
+			t.Fatal("no candle received")
+		}
+	}
+
+	require.NoError(t, underTest.UnsubscribeCandles(ctx, []string{"OIL_CRUDE"}, capitalcom.ResolutionMinute))
+
+	req = <-requests
+	assert.Equal(t, "OHLCMarketData.unsubscribe", req.Destination)
+	assert.JSONEq(t, `{"epics":["OIL_CRUDE"],"resolutions":["MINUTE"],"types":["classic"]}`, string(req.Payload))
+}
+
+func TestStream_ReconnectsAndRestoresSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+	subscriptions := make(chan streamingRequest, 10)
+
+	var connections atomic.Int32
+
+	streamingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		connection := connections.Add(1)
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			req := streamingRequest{}
+			_ = json.Unmarshal(data, &req)
+
+			if req.Destination == "ping" {
+				_ = conn.WriteMessage(websocket.TextMessage,
+					[]byte(`{"status":"OK","destination":"ping","correlationId":"`+req.CorrelationID+`","payload":{}}`))
+
+				continue
+			}
+
+			subscriptions <- req
+
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(subscriptionResponse(req, "PROCESSED")))
+
+			// the first connection drops right after the subscriptions
+			if connection == 1 && req.Destination == "OHLCMarketData.subscribe" {
+				return
+			}
+		}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream(
+		capitalcom.WithStreamReconnectBackoff(time.Millisecond, 10*time.Millisecond))
+	require.NoError(t, underTest.Connect(ctx))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	require.Equal(t, capitalcom.StreamStateConnected, (<-underTest.States()).State)
+
+	// Act
+	require.NoError(t, underTest.SubscribeQuotes(ctx, "OIL_CRUDE"))
+	require.NoError(t, underTest.SubscribeCandles(ctx, []string{"GOLD"}, capitalcom.ResolutionMinute))
+
+	// Assert
+	expectedStates := []capitalcom.StreamState{
+		capitalcom.StreamStateDisconnected,
+		capitalcom.StreamStateReconnecting,
+		capitalcom.StreamStateConnected,
+	}
+
+	for _, expectedState := range expectedStates {
+		select {
+		case state := <-underTest.States():
+			require.Equal(t, expectedState, state.State)
+		case <-time.After(time.Second):
+			t.Fatalf("state %s is not reported", expectedState)
+		}
+	}
+
+	received := make(map[string][]string)
+
+	for range 4 {
+		req := <-subscriptions
+		received[req.Destination] = append(received[req.Destination], string(req.Payload))
+	}
+
+	assert.Equal(t, int32(2), connections.Load())
+
+	for _, destination := range []string{"marketData.subscribe", "OHLCMarketData.subscribe"} {
+		require.Len(t, received[destination], 2)
+		assert.JSONEq(t, received[destination][0], received[destination][1])
+	}
+}
+
+func TestStream_ReconnectDiscardsIncompleteCandles(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var connections atomic.Int32
+
+	streamingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		connection := connections.Add(1)
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			req := streamingRequest{}
+			_ = json.Unmarshal(data, &req)
+
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(subscriptionResponse(req, "PROCESSED")))
+
+			// the first connection drops after the bid side of the bar, the next one sends the ask side first
+			if connection == 1 {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"status":"OK","destination":"ohlc.event",`+
+					`"payload":{"resolution":"MINUTE","epic":"OIL_CRUDE","type":"classic","priceType":"bid",`+
+					`"t":1671714660000,"h":79.305,"l":79.295,"o":79.3,"c":79.305}}`))
+
+				return
+			}
+
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"status":"OK","destination":"ohlc.event",`+
+				`"payload":{"resolution":"MINUTE","epic":"OIL_CRUDE","type":"classic","priceType":"ask",`+
+				`"t":1671714660000,"h":79.335,"l":79.325,"o":79.33,"c":79.335}}`))
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"status":"OK","destination":"ohlc.event",`+
+				`"payload":{"resolution":"MINUTE","epic":"OIL_CRUDE","type":"classic","priceType":"bid",`+
+				`"t":1671714660000,"h":79.405,"l":79.295,"o":79.3,"c":79.4}}`))
+		}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream(
+		capitalcom.WithStreamReconnectBackoff(time.Millisecond, 10*time.Millisecond))
+	require.NoError(t, underTest.Connect(ctx))
+
+	t.Cleanup(func() {
+		_ = underTest.Close()
+	})
+
+	// Act
+	require.NoError(t, underTest.SubscribeCandles(ctx, []string{"OIL_CRUDE"}, capitalcom.ResolutionMinute))
+
+	// Assert
+	select {
+	case candle := <-underTest.Candles():
+		assert.Equal(t, capitalcom.PriceData{Bid: 79.4, Ask: 79.335}, candle.Price.ClosePrice)
+	case <-time.After(time.Second):
+		t.Fatal("no candle received")
+	}
+
+	assert.Equal(t, int32(2), connections.Load())
+}
+
+func TestStream_DropsConnectionWithoutPingResponse(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	ctx := context.Background()
+
+	var connections atomic.Int32
+
+	streamingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wstest.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		connections.Add(1)
+
+		// pings are never answered
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	t.Cleanup(func() {
+		streamingSrv.Close()
+	})
+
+	underTest := newStreamingClient(t, streamingSrv).Stream(
+		capitalcom.WithStreamPingInterval(10*time.Millisecond),
+		capitalcom.WithStreamPingTimeout(20*time.Millisecond),
+		capitalcom.WithStreamReconnect(false))
+
+	// Act
+	require.NoError(t, underTest.Connect(ctx))
+
+	// Assert
+	select {
+	case <-underTest.Done():
+	case <-time.After(time.Second):
+		t.Fatal("the dead connection is not detected")
+	}
+
+	var connErr capitalcom.StreamConnectionError
+
+	require.ErrorAs(t, underTest.Err(), &connErr)
+	require.Equal(t, int32(1), connections.Load())
+}
@@ -16,6 +16,7 @@ type (
 		Date           time.Time      `json:"-"`
 		Status         string         `json:"status"`
 		DealStatus     string         `json:"dealStatus"`
+		RejectReason   string         `json:"rejectReason"`
 		Epic           string         `json:"epic"`
 		DealReference  string         `json:"dealReference"`
 		DealID         string         `json:"dealId"`
@@ -0,0 +1,383 @@
+package capitalcom
+
+import (
+	"fmt"
+	"math"
+)
+
+// Units of the dealing rules.
+const (
+	RuleUnitPoints     = "POINTS"
+	RuleUnitPercentage = "PERCENTAGE"
+)
+
+const trailingStopsNotAvailable = "NOT_AVAILABLE"
+
+// sizeIncrementTolerance is a share of the size increment ignored when checking a size is a multiple of it,
+// so float rounding errors aren't reported as violations.
+const sizeIncrementTolerance = 1e-6
+
+// Violation describes a request field breaking a dealing rule or a combination of fields which is not allowed.
+type Violation struct {
+	// Field is a JSON name of the request field, e.g. "stopDistance"
+	Field   string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Field + ": " + v.Message
+}
+
+// Validator checks trading requests against the dealing rules of a market and the account preferences
+// before they are sent, so invalid requests don't end up as rejected deals.
+type Validator struct {
+	market      MarketDetails
+	hedgingMode bool
+}
+
+// NewValidator creates a validator for the market, see Markets.Detail and Account.Preferences.
+func NewValidator(market MarketDetails, preferences Preferences) *Validator {
+	return &Validator{
+		market:      market,
+		hedgingMode: preferences.HedgingMode,
+	}
+}
+
+// ValidateOpenPosition checks the request to open a position, it returns a ValidationError listing all violations.
+// Distances in percents are calculated from the current market price of the snapshot.
+func (v *Validator) ValidateOpenPosition(req OpenPositionRequest) error {
+	var violations []Violation
+
+	violations = append(violations, validateDirection(req.Direction)...)
+	violations = append(violations, v.validateSize(req.Size)...)
+	violations = append(violations, v.validateStopsAndProfits(
+		req.Direction, v.marketPrice(req.Direction), positionStopsAndProfits(req.UpdatePositionRequest))...)
+
+	return newValidationError(violations)
+}
+
+// ValidateUpdatePosition checks the request to update a position in the direction.
+func (v *Validator) ValidateUpdatePosition(direction PositionDirection, req UpdatePositionRequest) error {
+	var violations []Violation
+
+	violations = append(violations, validateDirection(direction)...)
+	violations = append(violations, v.validateStopsAndProfits(
+		direction, v.marketPrice(direction), positionStopsAndProfits(req))...)
+
+	return newValidationError(violations)
+}
+
+// ValidateCreateOrder checks the request to create a working order.
+// Stop and profit levels and distances are checked relative to the order level.
+func (v *Validator) ValidateCreateOrder(req CreateOrderRequest) error {
+	var violations []Violation
+
+	violations = append(violations, validateDirection(req.Direction)...)
+	violations = append(violations, v.validateSize(req.Size)...)
+	violations = append(violations, v.validateOrder(req.Direction, req.UpdateOrderRequest)...)
+
+	return newValidationError(violations)
+}
+
+// ValidateUpdateOrder checks the request to update a working order in the direction.
+func (v *Validator) ValidateUpdateOrder(direction PositionDirection, req UpdateOrderRequest) error {
+	var violations []Violation
+
+	violations = append(violations, validateDirection(direction)...)
+	violations = append(violations, v.validateOrder(direction, req)...)
+
+	return newValidationError(violations)
+}
+
+func validateDirection(direction PositionDirection) []Violation {
+	if direction != PositionDirectionBuy && direction != PositionDirectionSell {
+		return []Violation{{Field: "direction", Message: fmt.Sprintf("unknown direction %q", direction)}}
+	}
+
+	return nil
+}
+
+func (v *Validator) validateSize(size float64) []Violation {
+	rules := v.market.DealingRules
+
+	var violations []Violation
+
+	if size <= 0 {
+		return []Violation{{Field: "size", Message: "must be positive"}}
+	}
+
+	if minSize := rules.MinDealSize.Value; size < minSize {
+		violations = append(violations, Violation{Field: "size", Message: fmt.Sprintf("must be at least %g", minSize)})
+	}
+
+	if maxSize := rules.MaxDealSize.Value; maxSize > 0 && size > maxSize {
+		violations = append(violations, Violation{Field: "size", Message: fmt.Sprintf("must be at most %g", maxSize)})
+	}
+
+	if increment := rules.MinSizeIncrement.Value; increment > 0 && !isMultipleOf(size, increment) {
+		violations = append(violations, Violation{
+			Field:   "size",
+			Message: fmt.Sprintf("must be a multiple of %g", increment),
+		})
+	}
+
+	return violations
+}
+
+func (v *Validator) validateOrder(direction PositionDirection, req UpdateOrderRequest) []Violation {
+	if req.Level <= 0 {
+		return []Violation{{Field: "level", Message: "must be positive"}}
+	}
+
+	return v.validateStopsAndProfits(direction, req.Level, orderStopsAndProfits(req))
+}
+
+// stopsAndProfits are the stop loss and take profit fields shared by the positions and working orders requests.
+type stopsAndProfits struct {
+	guaranteedStop bool
+	trailingStop   bool
+	stopLevel      float64
+	stopDistance   float64
+	stopAmount     float64
+	profitLevel    float64
+	profitDistance float64
+	profitAmount   float64
+}
+
+func positionStopsAndProfits(req UpdatePositionRequest) stopsAndProfits {
+	return stopsAndProfits{
+		guaranteedStop: req.GuaranteedStop,
+		trailingStop:   req.TrailingStop,
+		stopLevel:      req.StopLevel,
+		stopDistance:   req.StopDistance,
+		stopAmount:     req.StopAmount,
+		profitLevel:    req.ProfitLevel,
+		profitDistance: req.ProfitDistance,
+		profitAmount:   req.ProfitAmount,
+	}
+}
+
+func orderStopsAndProfits(req UpdateOrderRequest) stopsAndProfits {
+	return stopsAndProfits{
+		guaranteedStop: req.GuaranteedStop,
+		trailingStop:   req.TrailingStop,
+		stopLevel:      req.StopLevel,
+		stopDistance:   req.StopDistance,
+		stopAmount:     req.StopAmount,
+		profitLevel:    req.ProfitLevel,
+		profitDistance: req.ProfitDistance,
+		profitAmount:   req.ProfitAmount,
+	}
+}
+
+// validateStopsAndProfits checks the combination of the stop and profit fields and their distances
+// from the reference price, which is the market price for positions and the order level for working orders.
+func (v *Validator) validateStopsAndProfits(
+	direction PositionDirection,
+	reference float64,
+	req stopsAndProfits,
+) []Violation {
+	var violations []Violation
+
+	violations = append(violations, v.validateStopType(req)...)
+
+	if countSet(req.stopLevel, req.stopDistance, req.stopAmount) > 1 {
+		violations = append(violations, Violation{
+			Field:   "stopLevel",
+			Message: "only one of stopLevel, stopDistance and stopAmount can be set",
+		})
+	}
+
+	if countSet(req.profitLevel, req.profitDistance, req.profitAmount) > 1 {
+		violations = append(violations, Violation{
+			Field:   "profitLevel",
+			Message: "only one of profitLevel, profitDistance and profitAmount can be set",
+		})
+	}
+
+	stopDistance := req.stopDistance
+	profitDistance := req.profitDistance
+
+	isBuy := direction == PositionDirectionBuy
+
+	if req.stopLevel > 0 && reference > 0 {
+		if isBuy && req.stopLevel >= reference || !isBuy && req.stopLevel <= reference {
+			violations = append(violations, Violation{
+				Field:   "stopLevel",
+				Message: fmt.Sprintf("must be on the losing side of the price %g", reference),
+			})
+		} else {
+			stopDistance = math.Abs(reference - req.stopLevel)
+		}
+	}
+
+	if req.profitLevel > 0 && reference > 0 {
+		if isBuy && req.profitLevel <= reference || !isBuy && req.profitLevel >= reference {
+			violations = append(violations, Violation{
+				Field:   "profitLevel",
+				Message: fmt.Sprintf("must be on the profitable side of the price %g", reference),
+			})
+		} else {
+			profitDistance = math.Abs(req.profitLevel - reference)
+		}
+	}
+
+	stopField := "stopDistance"
+	if req.stopLevel > 0 {
+		stopField = "stopLevel"
+	}
+
+	profitField := "profitDistance"
+	if req.profitLevel > 0 {
+		profitField = "profitLevel"
+	}
+
+	violations = append(violations, v.validateStopDistance(stopField, stopDistance, reference, req.guaranteedStop)...)
+	violations = append(violations, v.validateDistance(profitField, profitDistance, reference)...)
+
+	return violations
+}
+
+func (v *Validator) validateStopType(req stopsAndProfits) []Violation {
+	var violations []Violation
+
+	hasStop := req.stopLevel > 0 || req.stopDistance > 0 || req.stopAmount > 0
+
+	if req.guaranteedStop {
+		if req.trailingStop {
+			violations = append(violations, Violation{
+				Field:   "guaranteedStop",
+				Message: "cannot be set together with trailingStop",
+			})
+		}
+
+		if v.hedgingMode {
+			violations = append(violations, Violation{
+				Field:   "guaranteedStop",
+				Message: "cannot be set in the hedging mode",
+			})
+		}
+
+		if !v.market.Instrument.GuaranteedStopAllowed {
+			violations = append(violations, Violation{
+				Field:   "guaranteedStop",
+				Message: "is not allowed for the instrument",
+			})
+		}
+
+		if !hasStop {
+			violations = append(violations, Violation{
+				Field:   "guaranteedStop",
+				Message: "requires stopLevel, stopDistance or stopAmount",
+			})
+		}
+	}
+
+	if req.trailingStop {
+		if req.stopDistance <= 0 {
+			violations = append(violations, Violation{
+				Field:   "trailingStop",
+				Message: "requires stopDistance",
+			})
+		}
+
+		if v.market.DealingRules.TrailingStopsPreference == trailingStopsNotAvailable {
+			violations = append(violations, Violation{
+				Field:   "trailingStop",
+				Message: "is not available for the instrument",
+			})
+		}
+	}
+
+	return violations
+}
+
+func (v *Validator) validateStopDistance(field string, distance, reference float64, guaranteed bool) []Violation {
+	violations := v.validateDistance(field, distance, reference)
+
+	if !guaranteed || distance <= 0 {
+		return violations
+	}
+
+	if minDistance, ok := ruleDistance(v.market.DealingRules.MinGuaranteedStopDistance, reference); ok &&
+		distance < minDistance {
+		violations = append(violations, Violation{
+			Field:   field,
+			Message: fmt.Sprintf("the guaranteed stop distance must be at least %g", minDistance),
+		})
+	}
+
+	return violations
+}
+
+func (v *Validator) validateDistance(field string, distance, reference float64) []Violation {
+	if distance <= 0 {
+		return nil
+	}
+
+	rules := v.market.DealingRules
+
+	var violations []Violation
+
+	if minDistance, ok := ruleDistance(rules.MinStopOrProfitDistance, reference); ok && distance < minDistance {
+		violations = append(violations, Violation{
+			Field:   field,
+			Message: fmt.Sprintf("the distance must be at least %g", minDistance),
+		})
+	}
+
+	if maxDistance, ok := ruleDistance(rules.MaxStopOrProfitDistance, reference); ok && distance > maxDistance {
+		violations = append(violations, Violation{
+			Field:   field,
+			Message: fmt.Sprintf("the distance must be at most %g", maxDistance),
+		})
+	}
+
+	return violations
+}
+
+// marketPrice returns the price a position in the direction is opened at.
+func (v *Validator) marketPrice(direction PositionDirection) float64 {
+	if direction == PositionDirectionSell {
+		return v.market.Snapshot.Bid
+	}
+
+	return v.market.Snapshot.Offer
+}
+
+// ruleDistance converts the distance rule to price points, a percentage is calculated from the reference price.
+// It returns false if the rule is not set or can't be converted.
+func ruleDistance(rule Rule, reference float64) (float64, bool) {
+	if rule.Value <= 0 {
+		return 0, false
+	}
+
+	if rule.Unit == RuleUnitPercentage {
+		if reference <= 0 {
+			return 0, false
+		}
+
+		return reference * rule.Value / 100, true //nolint:mnd
+	}
+
+	return rule.Value, true
+}
+
+func isMultipleOf(value, increment float64) bool {
+	remainder := math.Mod(value, increment)
+	tolerance := increment * sizeIncrementTolerance
+
+	return remainder < tolerance || increment-remainder < tolerance
+}
+
+func countSet(values ...float64) int {
+	count := 0
+
+	for _, value := range values {
+		if value != 0 {
+			count++
+		}
+	}
+
+	return count
+}
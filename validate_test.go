@@ -0,0 +1,263 @@
+package capitalcom_test
+
+import (
+	"testing"
+
+	"github.com/gromson/capitalcom"
+	"github.com/stretchr/testify/require"
+)
+
+func validationMarket() capitalcom.MarketDetails {
+	return capitalcom.MarketDetails{
+		Instrument: capitalcom.Instrument{
+			Epic:                  "SILVER",
+			GuaranteedStopAllowed: true,
+		},
+		DealingRules: capitalcom.DealingRules{
+			MinDealSize:               capitalcom.Rule{Unit: capitalcom.RuleUnitPoints, Value: 1},
+			MaxDealSize:               capitalcom.Rule{Unit: capitalcom.RuleUnitPoints, Value: 1000},
+			MinSizeIncrement:          capitalcom.Rule{Unit: capitalcom.RuleUnitPoints, Value: 0.5},
+			MinStopOrProfitDistance:   capitalcom.Rule{Unit: capitalcom.RuleUnitPercentage, Value: 0.1},
+			MaxStopOrProfitDistance:   capitalcom.Rule{Unit: capitalcom.RuleUnitPercentage, Value: 50},
+			MinGuaranteedStopDistance: capitalcom.Rule{Unit: capitalcom.RuleUnitPercentage, Value: 1},
+			TrailingStopsPreference:   "AVAILABLE",
+		},
+		Snapshot: capitalcom.Snapshot{
+			Bid:   99.9,
+			Offer: 100,
+		},
+	}
+}
+
+func requireViolations(t *testing.T, err error, fields ...string) {
+	t.Helper()
+
+	if len(fields) == 0 {
+		require.NoError(t, err)
+
+		return
+	}
+
+	var validationErr capitalcom.ValidationError
+
+	require.ErrorAs(t, err, &validationErr)
+
+	actual := make([]string, 0, len(validationErr.Violations()))
+
+	for _, violation := range validationErr.Violations() {
+		actual = append(actual, violation.Field)
+	}
+
+	require.Equal(t, fields, actual)
+}
+
+func TestValidator_ValidateOpenPosition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         capitalcom.OpenPositionRequest
+		hedgingMode bool
+		violations  []string
+	}{
+		{
+			name: "valid request",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Epic:      "SILVER",
+				Size:      1.5,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					StopLevel:   95,
+					ProfitLevel: 110,
+				},
+			},
+		},
+		{
+			name: "unknown direction",
+			req: capitalcom.OpenPositionRequest{
+				Direction: "HOLD",
+				Size:      1,
+			},
+			violations: []string{"direction"},
+		},
+		{
+			name: "size below minimum and not a multiple of the increment",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      0.7,
+			},
+			violations: []string{"size", "size"},
+		},
+		{
+			name: "size above maximum",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionSell,
+				Size:      1001,
+			},
+			violations: []string{"size"},
+		},
+		{
+			name: "stop level on the profitable side",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionSell,
+				Size:      1,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					StopLevel: 95,
+				},
+			},
+			violations: []string{"stopLevel"},
+		},
+		{
+			name: "stop distance below minimum",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      1,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					StopDistance: 0.05,
+				},
+			},
+			violations: []string{"stopDistance"},
+		},
+		{
+			name: "profit distance above maximum",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      1,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					ProfitDistance: 60,
+				},
+			},
+			violations: []string{"profitDistance"},
+		},
+		{
+			name: "guaranteed stop below minimum guaranteed distance",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      1,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					GuaranteedStop: true,
+					StopDistance:   0.5,
+				},
+			},
+			violations: []string{"stopDistance"},
+		},
+		{
+			name: "guaranteed stop in the hedging mode",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      1,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					GuaranteedStop: true,
+					StopDistance:   5,
+				},
+			},
+			hedgingMode: true,
+			violations:  []string{"guaranteedStop"},
+		},
+		{
+			name: "guaranteed stop with trailing stop and without stop",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      1,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					GuaranteedStop: true,
+					TrailingStop:   true,
+				},
+			},
+			violations: []string{"guaranteedStop", "guaranteedStop", "trailingStop"},
+		},
+		{
+			name: "several stops and profits",
+			req: capitalcom.OpenPositionRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      1,
+				UpdatePositionRequest: capitalcom.UpdatePositionRequest{
+					StopDistance: 5,
+					StopAmount:   10,
+					ProfitLevel:  110,
+					ProfitAmount: 10,
+				},
+			},
+			violations: []string{"stopLevel", "profitLevel"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			underTest := capitalcom.NewValidator(validationMarket(), capitalcom.Preferences{HedgingMode: tt.hedgingMode})
+
+			requireViolations(t, underTest.ValidateOpenPosition(tt.req), tt.violations...)
+		})
+	}
+}
+
+func TestValidator_ValidateCreateOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		req        capitalcom.CreateOrderRequest
+		violations []string
+	}{
+		{
+			name: "valid request",
+			req: capitalcom.CreateOrderRequest{
+				Direction: capitalcom.PositionDirectionSell,
+				Size:      2,
+				Type:      capitalcom.LimitOrder,
+				UpdateOrderRequest: capitalcom.UpdateOrderRequest{
+					Level:        120,
+					StopLevel:    125,
+					TrailingStop: false,
+				},
+			},
+		},
+		{
+			name: "missing level",
+			req: capitalcom.CreateOrderRequest{
+				Direction: capitalcom.PositionDirectionSell,
+				Size:      2,
+				Type:      capitalcom.LimitOrder,
+			},
+			violations: []string{"level"},
+		},
+		{
+			name: "profit level relative to the order level",
+			req: capitalcom.CreateOrderRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      2,
+				Type:      capitalcom.StopOrder,
+				UpdateOrderRequest: capitalcom.UpdateOrderRequest{
+					Level:       120,
+					ProfitLevel: 110,
+				},
+			},
+			violations: []string{"profitLevel"},
+		},
+		{
+			name: "trailing stop without distance",
+			req: capitalcom.CreateOrderRequest{
+				Direction: capitalcom.PositionDirectionBuy,
+				Size:      2,
+				Type:      capitalcom.StopOrder,
+				UpdateOrderRequest: capitalcom.UpdateOrderRequest{
+					Level:        120,
+					TrailingStop: true,
+				},
+			},
+			violations: []string{"trailingStop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			underTest := capitalcom.NewValidator(validationMarket(), capitalcom.Preferences{})
+
+			requireViolations(t, underTest.ValidateCreateOrder(tt.req), tt.violations...)
+		})
+	}
+}